@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	passwordFlag = "password"
+	roleFlag     = "role"
+	amountFlag   = "amount"
+)
+
+// newAdminCmd операторские задачи, которые выполняются напрямую в БД, например, до появления первого администратора
+func newAdminCmd(cfg *conf.App) *cobra.Command {
+	adminCmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Run operator tasks against DB",
+	}
+	adminCmd.AddCommand(
+		newCreateUserCmd(cfg),
+		newResetPasswordCmd(cfg),
+		newAdjustBalanceCmd(cfg),
+		newRequeueOrderCmd(cfg),
+	)
+	return adminCmd
+}
+
+func newCreateUserCmd(cfg *conf.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-user LOGIN",
+		Short: "Create user, password is read from stdin when --password is not set",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			role, err := user.ParseRole(cmd.Flag(roleFlag).Value.String())
+			if err != nil {
+				return err
+			}
+			pword, err := readPassword(cmd)
+			if err != nil {
+				return err
+			}
+			d, err := openDomain(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			defer d.Close()
+
+			// пользователь не должен остаться с ролью по умолчанию, если роль выдать не удалось
+			var usr user.User
+			err = d.tx.Do(cmd.Context(), func(ctx context.Context) error {
+				usr, err = d.accounts.SignIn(ctx, args[0], pword)
+				if err != nil || role == usr.Role {
+					return err
+				}
+				return d.users.ChangeRole(ctx, usr, role)
+			})
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "user %s is created with id %s and role %s\n", args[0], usr.ID, role)
+			return err
+		},
+	}
+	cmd.Flags().String(passwordFlag, "", "sets password of new user")
+	cmd.Flags().String(roleFlag, string(user.RoleUser), "sets role of new user: user, support, admin")
+	return cmd
+}
+
+func newResetPasswordCmd(cfg *conf.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset-password LOGIN",
+		Short: "Set new password, it is read from stdin when --password is not set",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pword, err := readPassword(cmd)
+			if err != nil {
+				return err
+			}
+			d, err := openDomain(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			defer d.Close()
+
+			usr, err := d.creds.GetUser(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			err = d.creds.SetPassword(cmd.Context(), usr, pword)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "password of user %s is changed\n", args[0])
+			return err
+		},
+	}
+	cmd.Flags().String(passwordFlag, "", "sets new password")
+	return cmd
+}
+
+func newAdjustBalanceCmd(cfg *conf.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adjust-balance LOGIN --amount SUM",
+		Short: "Add SUM to collected points of user, negative SUM takes points back",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			delta, err := parseAmount(cmd.Flag(amountFlag).Value.String())
+			if err != nil {
+				return err
+			}
+			d, err := openDomain(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			defer d.Close()
+
+			usr, err := d.creds.GetUser(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			bal, err := d.operator.AdjustBalance(cmd.Context(), usr, delta)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "balance of user %s: collected %s, withdrawn %s, current %s\n",
+				args[0], bal.Collected, bal.Withdrawn, bal.Current)
+			return err
+		},
+	}
+	cmd.Flags().String(amountFlag, "", "sets sum to add, e.g. 100.50 or -20")
+	_ = cmd.MarkFlagRequired(amountFlag)
+	return cmd
+}
+
+func newRequeueOrderCmd(cfg *conf.App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "requeue-order NUMBER",
+		Short: "Send order to accrual system again, processed orders are not requeued",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, err := openDomain(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			defer d.Close()
+
+			ord, err := d.operator.RequeueOrder(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "order %s is requeued with status %s\n", ord.Number, ord.Status)
+			return err
+		},
+	}
+}
+
+// readPassword пароль из stdin не попадает в историю команд и список процессов
+func readPassword(cmd *cobra.Command) (string, error) {
+	pword := cmd.Flag(passwordFlag).Value.String()
+	if pword == "" {
+		line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		pword = strings.TrimRight(line, "\r\n")
+	}
+	if pword == "" {
+		return "", errors2.ErrPasswordIsEmpty
+	}
+	return pword, nil
+}
+
+// parseAmount primit.Float64ToCurrency округляет только положительные суммы, поэтому знак обрабатывается отдельно
+func parseAmount(arg string) (primit.Currency, error) {
+	f, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, errors.Errorf("expected a sum, got %q", arg)
+	}
+	if f < 0 {
+		return -primit.Float64ToCurrency(-f), nil
+	}
+	return primit.Float64ToCurrency(f), nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    primit.Currency
+		wantErr bool
+	}{
+		{name: "integer", arg: "100", want: 10000},
+		{name: "fraction", arg: "100.55", want: 10055},
+		{name: "negative fraction", arg: "-1.5", want: -150},
+		{name: "not a sum", arg: "ten", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAmount(tt.arg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		stdin   string
+		want    string
+		wantErr error
+	}{
+		{name: "from flag", flag: "secret", stdin: "other\n", want: "secret"},
+		{name: "from stdin", stdin: "secret\r\nnext line\n", want: "secret"},
+		{name: "from stdin without new line", stdin: "secret", want: "secret"},
+		{name: "empty", stdin: "\n", wantErr: errors2.ErrPasswordIsEmpty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().String(passwordFlag, "", "")
+			require.NoError(t, cmd.Flags().Set(passwordFlag, tt.flag))
+			cmd.SetIn(bytes.NewBufferString(tt.stdin))
+
+			got, err := readPassword(cmd)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCmd(cfg *conf.App) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect configuration",
+	}
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "print",
+		Short: "Print effective configuration with secrets masked",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// конфиг с ошибками тоже выводится, чтобы было видно, откуда взялись неверные значения
+			err := conf.Load(cmd.Flags(), cfg)
+			var verr *conf.ValidationError
+			if err != nil && !errors.As(err, &verr) {
+				return err
+			}
+			errPrint := cfg.Print(cmd.OutOrStdout())
+			if errPrint != nil {
+				return errPrint
+			}
+			return err
+		},
+	})
+	return configCmd
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/uow"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/infra/persist/postgre"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/server"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/spf13/cobra"
+)
+
+// domain сервисы для команд, которые работают с данными напрямую, минуя HTTP API.
+// Логины и пароли проходят ту же политику, что и при регистрации через API.
+type domain struct {
+	db       *pgxpool.Pool
+	tx       uow.UnitOfWork
+	creds    *auth.Manager
+	users    *user.Service
+	accounts *auth.Service
+	orders   *service.Order
+	operator *service.Operator
+}
+
+func openDomain(cmd *cobra.Command, cfg *conf.App) (*domain, error) {
+	err := conf.Load(cmd.Flags(), &cfg.Database, &cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	ctx := cmd.Context()
+	db, err := connectDB(ctx, cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := postgre.NewPersist(ctx, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	policy, err := server.NewCredentialPolicy(cfg.Credentials)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	d := &domain{db: db, tx: repo}
+	d.creds = auth.NewManagerWithPolicy(repo.Auth, auth.NewDefaultHasher(), policy)
+	d.users = user.NewService(repo.User)
+	d.accounts = auth.NewService(d.users, d.creds, repo)
+	d.orders = service.NewOrder(repo.Order)
+	d.operator = service.NewOperator(repo.Balance, repo.Order)
+	return d, nil
+}
+
+func (d *domain) Close() {
+	d.db.Close()
+}
+
+// connectDB в отличие от сервера миграции не накатывает: команды работают только с актуальной схемой
+func connectDB(ctx context.Context, cfg conf.Database) (*pgxpool.Pool, error) {
+	db, err := pgxpool.Connect(ctx, cfg.URI.Reveal())
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := postgre.NewMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	err = migrator.CheckUpToDate(ctx)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
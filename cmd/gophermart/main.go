@@ -1,15 +1,18 @@
 package main
 
 import (
-	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/server"
+	"os"
+
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
-	// Warning! init() is in use!
-	srv, err := server.NewServer(cfg)
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	err := newRootCmd().Execute()
 	if err != nil {
-		log.Fatal().Err(err).Msg("server is not configured")
+		os.Exit(1)
 	}
-	srv.Run()
 }
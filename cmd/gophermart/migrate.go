@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/infra/persist/postgre"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// newMigrateCmd управляет схемой БД отдельно от сервера, например из пайплайна деплоя до выкатки новых экземпляров.
+// Командам нужна только секция Database.
+func newMigrateCmd(cfg *conf.App) *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage DB schema",
+	}
+	migrateCmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply all pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withMigrator(cmd, cfg, func(ctx context.Context, m *postgre.Migrator) error {
+					return m.Up(ctx)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "down N",
+			Short: "Roll back N last migrations",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				n, err := parseNumber(args[0])
+				if err != nil {
+					return err
+				}
+				return withMigrator(cmd, cfg, func(ctx context.Context, m *postgre.Migrator) error {
+					return m.Down(ctx, n)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Show schema version and state of each migration",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withMigrator(cmd, cfg, func(ctx context.Context, m *postgre.Migrator) error {
+					status, err := m.Status(ctx)
+					if err != nil {
+						return err
+					}
+					return printStatus(cmd.OutOrStdout(), status)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "force V",
+			Short: "Set schema version V after failed migration, -- -1 after all flags means empty schema",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				v, err := parseNumber(args[0])
+				if err != nil {
+					return err
+				}
+				return withMigrator(cmd, cfg, func(ctx context.Context, m *postgre.Migrator) error {
+					return m.Force(ctx, v)
+				})
+			},
+		},
+	)
+	return migrateCmd
+}
+
+// withMigrator миграции подключаются к БД сами, поэтому connectDB с проверкой схемы здесь не подходит
+func withMigrator(cmd *cobra.Command, cfg *conf.App, action func(ctx context.Context, m *postgre.Migrator) error) error {
+	err := conf.Load(cmd.Flags(), &cfg.Database)
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	db, err := pgxpool.Connect(ctx, cfg.Database.URI.Reveal())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := postgre.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	return action(ctx, migrator)
+}
+
+func parseNumber(arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, errors.Errorf("expected a number, got %q", arg)
+	}
+	return n, nil
+}
+
+func printStatus(out io.Writer, status postgre.SchemaStatus) error {
+	_, err := fmt.Fprintf(out, "version: %d, dirty: %v, up to date: %v\n", status.Version, status.Dirty, status.UpToDate())
+	if err != nil {
+		return err
+	}
+	for _, state := range status.Migrations {
+		mark := "pending"
+		switch {
+		case state.Changed:
+			mark = "changed"
+		case state.Applied:
+			mark = "applied"
+		}
+		_, err = fmt.Fprintf(out, "%4d %-20s %s\n", state.Version, state.Name, mark)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
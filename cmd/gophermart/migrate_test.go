@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/infra/persist/postgre"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    int
+		wantErr bool
+	}{
+		{name: "positive", arg: "2", want: 2},
+		{name: "nil version", arg: "-1", want: -1},
+		{name: "not a number", arg: "all", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNumber(tt.arg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPrintStatus(t *testing.T) {
+	status := postgre.SchemaStatus{
+		Version: 2,
+		Migrations: []postgre.MigrationState{
+			{Migration: postgre.Migration{Version: 1, Name: "user"}, Applied: true},
+			{Migration: postgre.Migration{Version: 2, Name: "auth"}, Applied: true, Changed: true},
+			{Migration: postgre.Migration{Version: 3, Name: "order"}},
+		},
+	}
+	out := &bytes.Buffer{}
+	require.NoError(t, printStatus(out, status))
+	assert.Equal(t, "version: 2, dirty: false, up to date: false\n"+
+		"   1 user                 applied\n"+
+		"   2 auth                 changed\n"+
+		"   3 order                pending\n", out.String())
+}
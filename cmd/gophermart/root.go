@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
+	"github.com/spf13/cobra"
+)
+
+// newRootCmd дерево команд. Флаги конфига общие для всех команд, каждая команда читает через conf.Load
+// только нужные ей секции. Без команды запускается сервер, как и раньше.
+func newRootCmd() *cobra.Command {
+	cfg := conf.NewAppConfig()
+	root := &cobra.Command{
+		Use:          "gophermart",
+		Short:        "GopherMart loyalty system",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(cmd, cfg)
+		},
+	}
+	root.CompletionOptions.DisableDefaultCmd = true
+	cfg.SetPFlag(root.PersistentFlags())
+
+	root.AddCommand(
+		newServeCmd(cfg),
+		newMigrateCmd(cfg),
+		newAdminCmd(cfg),
+		newSeedCmd(cfg),
+		newConfigCmd(cfg),
+	)
+	return root
+}
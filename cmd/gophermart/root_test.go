@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRootCmd проверяются только ошибки, которые возникают до подключения к БД
+func TestRootCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "unknown command", args: []string{"unknown"}},
+		{name: "migrate down without number", args: []string{"migrate", "down"}},
+		{name: "migrate down not a number", args: []string{"migrate", "down", "all"}},
+		{name: "migrate without database", args: []string{"migrate", "up"}, wantErr: conf.ErrConfigDatabaseURINotSet},
+		{name: "create user with unknown role", args: []string{"admin", "create-user", "bob", "--role", "boss"}, wantErr: errors2.ErrRoleIsInvalid},
+		{name: "create user without password", args: []string{"admin", "create-user", "bob"}, wantErr: errors2.ErrPasswordIsEmpty},
+		{name: "adjust balance without amount", args: []string{"admin", "adjust-balance", "bob"}},
+		{name: "adjust balance not a sum", args: []string{"admin", "adjust-balance", "bob", "--amount", "ten"}},
+		{name: "seed without users", args: []string{"seed", "--users", "0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DATABASE_URI", "")
+			root := newRootCmd()
+			root.SetArgs(tt.args)
+			root.SetIn(&bytes.Buffer{})
+			root.SetOut(&bytes.Buffer{})
+			root.SetErr(&bytes.Buffer{})
+
+			err := root.Execute()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	seedUsersFlag    = "users"
+	seedOrdersFlag   = "orders"
+	seedPrefixFlag   = "login-prefix"
+	seedPasswordFlag = "password"
+
+	// seedOrderBase номера демо-заказов: 11 случайных цифр и контрольная цифра
+	seedOrderBase = 10_000_000_000
+)
+
+// newSeedCmd демо-данные для ручной проверки и стендов. Уже существующие логины пропускаются,
+// поэтому команду можно запускать повторно.
+func newSeedCmd(cfg *conf.App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Create demo users with orders",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			users, _ := cmd.Flags().GetInt(seedUsersFlag)
+			orders, _ := cmd.Flags().GetInt(seedOrdersFlag)
+			prefix, _ := cmd.Flags().GetString(seedPrefixFlag)
+			pword, _ := cmd.Flags().GetString(seedPasswordFlag)
+			if users <= 0 || orders < 0 {
+				return errors.New("users must be positive and orders must not be negative")
+			}
+
+			d, err := openDomain(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			defer d.Close()
+
+			rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+			for _, login := range seedLogins(prefix, users) {
+				usr, err := d.accounts.SignIn(cmd.Context(), login, pword)
+				if errors.Is(err, errors2.ErrLoginIsInUseAlready) {
+					log.Info().Msgf("user %s exists already, skipped", login)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				for i := 0; i < orders; i++ {
+					num := seedOrderNumber(rnd)
+					err = d.orders.Add(cmd.Context(), usr, num.String())
+					if err != nil {
+						return err
+					}
+				}
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "user %s is created with %d orders\n", login, orders)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int(seedUsersFlag, 3, "sets number of demo users")
+	cmd.Flags().Int(seedOrdersFlag, 5, "sets number of orders per demo user")
+	cmd.Flags().String(seedPrefixFlag, "demo", "sets login prefix of demo users, logins are prefix1, prefix2 and so on")
+	cmd.Flags().String(seedPasswordFlag, "Demo-password1", "sets password of all demo users")
+	return cmd
+}
+
+func seedLogins(prefix string, n int) []string {
+	logins := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		logins = append(logins, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return logins
+}
+
+func seedOrderNumber(rnd *rand.Rand) primit.LuhnNumber {
+	return primit.NewLuhnNumber(seedOrderBase + uint64(rnd.Int63n(9*seedOrderBase)))
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedLogins(t *testing.T) {
+	assert.Equal(t, []string{"demo1", "demo2", "demo3"}, seedLogins("demo", 3))
+}
+
+func TestSeedOrderNumber(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		num := seedOrderNumber(rnd)
+		assert.True(t, num.IsValid(), num.String())
+		assert.Len(t, num.String(), 12)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/server"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd(cfg *conf.App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Run HTTP server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(cmd, cfg)
+		},
+	}
+}
+
+func runServe(cmd *cobra.Command, cfg *conf.App) error {
+	err := conf.Load(cmd.Flags(), cfg)
+	if err != nil {
+		return err
+	}
+	logConfig(cfg)
+
+	srv, err := server.NewServer(cfg)
+	if err != nil {
+		return err
+	}
+	srv.Run()
+	return nil
+}
+
+func logConfig(cfg *conf.App) {
+	log.Info().Msgf("cfg: server addr is set to %v", cfg.RunAddress)
+	log.Info().Msgf("cfg: database uri is set to %v", cfg.URI)
+	log.Info().Msgf("cfg: auto-migrate is set to %v", cfg.AutoMigrate)
+	log.Info().Msgf("cfg: accrual system addr is set to %v", cfg.AccrualSystemAddress)
+	log.Info().Msgf("cfg: session idle ttl is set to %v, lifetime is set to %v", cfg.IdleTTL, cfg.Lifetime)
+	log.Info().Msgf("cfg: auth mode is set to %v", cfg.Mode)
+	log.Info().Msgf("cfg: admin API key is enabled: %v", cfg.AdminKeyEnabled())
+	log.Info().Msgf("cfg: login throttle store is set to %v", cfg.Throttle.Store)
+	log.Info().Msgf("cfg: two-factor issuer is set to %v, withdrawal threshold is %v", cfg.TwoFactor.Issuer, cfg.TwoFactor.WithdrawThreshold)
+}
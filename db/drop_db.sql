@@ -1,7 +1,21 @@
 -- DATABASE_URI=user=postgres password=postgres dbname=ya_pract sslmode=disable
-DROP TYPE IF EXISTS order_status;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS two_factor_recovery_codes;
+DROP TABLE IF EXISTS two_factors;
+DROP TABLE IF EXISTS login_attempts;
+DROP TABLE IF EXISTS password_resets;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS withdrawals;
+DROP TABLE IF EXISTS balances;
 DROP TABLE IF EXISTS orders;
+DROP TYPE IF EXISTS order_status;
 DROP FUNCTION IF EXISTS trigger_set_timestamp;
+DROP FUNCTION IF EXISTS trigger_collect_accrual;
 
 DROP TABLE IF EXISTS auth;
-DROP TABLE IF EXISTS users;
\ No newline at end of file
+DROP TABLE IF EXISTS users;
+DROP TYPE IF EXISTS user_status;
+DROP TYPE IF EXISTS user_role;
+DROP TABLE IF EXISTS schema_migration_checksums;
+DROP TABLE IF EXISTS schema_migrations;
\ No newline at end of file
@@ -0,0 +1,24 @@
+package access
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=./mocks/mock_attempt.go . AttemptStore
+
+// AttemptStore хранилище счетчиков попыток входа.
+// Попытка учитывается как неудачная еще до проверки пароля, иначе параллельные попытки успевают пройти
+// до того, как записана блокировка. Счетчик сбрасывается, если с последней попытки прошло больше окна.
+type AttemptStore interface {
+	// Acquire атомарно проверяет блокировку ключа и учитывает попытку.
+	// Если ключ заблокирован - попытка не учитывается и возвращается время окончания блокировки.
+	// Иначе возвращается нулевое время, а ключ сразу блокируется на lock(число попыток подряд), если оно больше нуля
+	Acquire(ctx context.Context, key string, lock func(attempts int) time.Duration) (lockedUntil time.Time, err error)
+	// Release отменяет учет попытки, которая не оказалась неудачной.
+	// Блокировка остается, только если lock от уменьшенного числа попыток больше нуля
+	Release(ctx context.Context, key string, lock func(attempts int) time.Duration) error
+	Reset(ctx context.Context, key string) error
+	// DeleteExpired удаляет счетчики, у которых истекли и окно, и блокировка
+	DeleteExpired(ctx context.Context) error
+}
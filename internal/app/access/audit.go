@@ -0,0 +1,27 @@
+package access
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockgen -destination=./mocks/mock_audit.go . AuditLog
+
+// AuditEntry запись о действии в административном API
+type AuditEntry struct {
+	// ActorID пользователь, выполнивший действие. Пусто, если запрос выполнен по ключу администратора
+	ActorID string
+	ByKey   bool
+	// Action метод и шаблон маршрута, например, POST /api/admin/users/{id}/disable
+	Action string
+	// Target фактический путь запроса с идентификаторами
+	Target    string
+	Status    int
+	ClientIP  string
+	RequestID string
+	At        time.Time
+}
+
+type AuditLog interface {
+	Record(ctx context.Context, entry AuditEntry) error
+}
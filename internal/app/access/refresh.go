@@ -0,0 +1,46 @@
+package access
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/google/uuid"
+)
+
+//go:generate mockgen -destination=./mocks/mock_refresh.go . RefreshTokenStore
+
+const refreshTokenLen = 32
+
+// RefreshTokenStore хранилище refresh-токенов.
+// Токены выдаются цепочками (family): каждый токен погашается при обмене на следующий в той же цепочке.
+type RefreshTokenStore interface {
+	// Issue открывает новую цепочку и возвращает ее первый токен
+	Issue(ctx context.Context, userRef Referencer) (token RefreshToken, err error)
+	// Rotate погашает токен и выдает следующий в той же цепочке.
+	// Если токен уже погашен - отзывает всю цепочку и возвращает errors.ErrRefreshTokenIsReused,
+	// если токена нет, он истек или отозван - errors.ErrRefreshTokenIsInvalid
+	Rotate(ctx context.Context, token RefreshToken) (ref string, next RefreshToken, err error)
+	// RevokeFamily отзывает цепочку, в которую входит токен
+	RevokeFamily(ctx context.Context, token RefreshToken) error
+	// RevokeUser отзывает все цепочки пользователя
+	RevokeUser(ctx context.Context, userRef string) error
+	// DeleteExpired удаляет все истекшие токены
+	DeleteExpired(ctx context.Context) error
+}
+
+type RefreshToken string
+
+func NewRefreshToken() RefreshToken {
+	b := make([]byte, refreshTokenLen)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+	return RefreshToken(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// NewRefreshTokenFamily идентификатор новой цепочки токенов
+func NewRefreshTokenFamily() string {
+	return uuid.New().String()
+}
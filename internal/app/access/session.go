@@ -0,0 +1,35 @@
+package access
+
+import (
+	"context"
+
+	_ "github.com/golang/mock/mockgen/model"
+	"github.com/google/uuid"
+)
+
+//go:generate mockgen -destination=./mocks/mock_session.go . SessionStore
+
+type Referencer interface {
+	Reference() string
+}
+
+// SessionStore хранилище сессий пользователей
+type SessionStore interface {
+	// AddNewSession открывает новую сессию пользователя
+	AddNewSession(ctx context.Context, userRef Referencer) (token SessionToken, err error)
+	// GetReference продлевает сессию и возвращает ссылку на ее пользователя.
+	// Если сессии нет или она истекла - возвращает errors.ErrSessionIsExpired
+	GetReference(ctx context.Context, token SessionToken) (ref string, err error)
+	// DeleteSession закрывает сессию
+	DeleteSession(ctx context.Context, token SessionToken) error
+	// DeleteUserSessions закрывает все сессии пользователя
+	DeleteUserSessions(ctx context.Context, userRef string) error
+	// DeleteExpired удаляет все истекшие сессии
+	DeleteExpired(ctx context.Context) error
+}
+
+type SessionToken string
+
+func NewSessionToken() SessionToken {
+	return SessionToken(uuid.New().String())
+}
@@ -9,11 +9,60 @@ import (
 	_ "github.com/golang/mock/mockgen/model"
 )
 
-//go:generate mockgen -destination=./mocks/mock_gophermart.go . Authenticator,OrderProcessor,BalanceGetter,WithdrawalProcessor
+//go:generate mockgen -destination=./mocks/mock_gophermart.go . Authenticator,PasswordManager,AccountManager,RoleManager,TwoFactorManager,OrderProcessor,BalanceGetter,WithdrawalProcessor,BackOffice
 
 type Authenticator interface {
-	SignIn(ctx context.Context, login, pword string) error
+	SignIn(ctx context.Context, login, pword string) (usr user.User, err error)
 	Login(ctx context.Context, login, pword string) (usr user.User, err error)
+	// NormalizeLogin приводит логин к виду, в котором он хранится, чтобы варианты написания одного логина
+	// считались одним логином и за пределами аутентификации, например, в счетчиках попыток входа
+	NormalizeLogin(login string) string
+	// CheckActive проверяет, что вход пользователю все еще разрешен. Нужна, когда доступ выдается
+	// не сразу после проверки пароля, например, после второго фактора: за это время пользователя могли отключить.
+	// Возвращает errors.ErrUserIsDisabled, если пользователь отключен, errors.ErrUserNotFound - если удален
+	CheckActive(ctx context.Context, usr user.User) error
+}
+
+type PasswordManager interface {
+	ChangePassword(ctx context.Context, usr user.User, oldPword, newPword string) error
+	// RequestPasswordReset отправляет владельцу логина одноразовый токен для сброса пароля.
+	// Не сообщает, существует ли логин.
+	RequestPasswordReset(ctx context.Context, login string) error
+	ResetPassword(ctx context.Context, token, newPword string) (usr user.User, err error)
+}
+
+// AccountManager управляет жизненным циклом учетных записей
+type AccountManager interface {
+	DisableUser(ctx context.Context, usr user.User) error
+	EnableUser(ctx context.Context, usr user.User) error
+	DeleteUser(ctx context.Context, usr user.User) error
+	// DeleteAccount удаление учетной записи самим пользователем с подтверждением паролем
+	DeleteAccount(ctx context.Context, usr user.User, pword string) error
+}
+
+// RoleManager роли пользователей для разграничения доступа
+type RoleManager interface {
+	UserRole(ctx context.Context, usr user.User) (user.Role, error)
+	ChangeRole(ctx context.Context, usr user.User, role user.Role) error
+}
+
+// TwoFactorSetup секрет для ввода вручную и ссылка otpauth:// для QR-кода
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TwoFactorManager второй фактор аутентификации по TOTP
+type TwoFactorManager interface {
+	SetupTwoFactor(ctx context.Context, usr user.User) (setup TwoFactorSetup, err error)
+	// ConfirmTwoFactor включает 2FA по первому коду из приложения и возвращает одноразовые коды восстановления
+	ConfirmTwoFactor(ctx context.Context, usr user.User, code string) (recovery []string, err error)
+	DisableTwoFactor(ctx context.Context, usr user.User, code string) error
+	// VerifyTwoFactor принимает код из приложения или код восстановления
+	VerifyTwoFactor(ctx context.Context, usr user.User, code string) error
+	// VerifyTOTP принимает только код из приложения
+	VerifyTOTP(ctx context.Context, usr user.User, code string) error
+	IsTwoFactorEnabled(ctx context.Context, usr user.User) (bool, error)
 }
 
 type OrderProcessor interface {
@@ -30,13 +79,58 @@ type WithdrawalProcessor interface {
 	List(ctx context.Context, user2 user.User) (wtdrwls []entity.Withdrawal, err error)
 }
 
+// BackOffice инструменты поддержки. Заказы, списания и баланс пользователя читаются через те же интерфейсы,
+// что и для самого пользователя
+type BackOffice interface {
+	// FindUsers ищет учетные записи по подстроке логина
+	FindUsers(ctx context.Context, login string, limit int) (accs []entity.Account, err error)
+	// RecheckOrder сверяет заказ с системой расчета начислений вне очереди
+	RecheckOrder(ctx context.Context, num string) (ord entity.Order, err error)
+}
+
 type GopherMart struct {
 	Authenticator
+	Passwords   PasswordManager
+	Accounts    AccountManager
+	Roles       RoleManager
+	TwoFactor   TwoFactorManager
+	Orders      OrderProcessor
+	Balance     BalanceGetter
+	Withdrawals WithdrawalProcessor
+	BackOffice  BackOffice
 }
 
-func NewGopherMart(auth Authenticator) *GopherMart {
+func NewGopherMart(auth Authenticator, pwords PasswordManager, accounts AccountManager, roles RoleManager,
+	twoFactor TwoFactorManager, orders OrderProcessor, balance BalanceGetter, wtdrwls WithdrawalProcessor,
+	backOffice BackOffice) *GopherMart {
 	if auth == nil {
 		panic("missing Authenticator, parameter must not be nil")
 	}
-	return &GopherMart{Authenticator: auth}
+	if pwords == nil {
+		panic("missing PasswordManager, parameter must not be nil")
+	}
+	if accounts == nil {
+		panic("missing AccountManager, parameter must not be nil")
+	}
+	if roles == nil {
+		panic("missing RoleManager, parameter must not be nil")
+	}
+	if twoFactor == nil {
+		panic("missing TwoFactorManager, parameter must not be nil")
+	}
+	if orders == nil {
+		panic("missing OrderProcessor, parameter must not be nil")
+	}
+	if balance == nil {
+		panic("missing BalanceGetter, parameter must not be nil")
+	}
+	if wtdrwls == nil {
+		panic("missing WithdrawalProcessor, parameter must not be nil")
+	}
+	if backOffice == nil {
+		panic("missing BackOffice, parameter must not be nil")
+	}
+	return &GopherMart{Authenticator: auth, Passwords: pwords, Accounts: accounts, Roles: roles,
+		TwoFactor: twoFactor, Orders: orders, Balance: balance, Withdrawals: wtdrwls,
+		BackOffice: backOffice}
 }
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/UndeadDemidov/ya-pr-diploma/internal/app (interfaces: Authenticator,OrderProcessor,BalanceGetter,WithdrawalProcessor)
+// Source: github.com/UndeadDemidov/ya-pr-diploma/internal/app (interfaces: Authenticator,PasswordManager,AccountManager,RoleManager,TwoFactorManager,OrderProcessor,BalanceGetter,WithdrawalProcessor,BackOffice)
 
 // Package mock_app is a generated GoMock package.
 package mock_app
@@ -8,7 +8,8 @@ import (
 	context "context"
 	reflect "reflect"
 
-	order "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	app "github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	entity "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
 	primit "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
 	user "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
 	gomock "github.com/golang/mock/gomock"
@@ -37,6 +38,20 @@ func (m *MockAuthenticator) EXPECT() *MockAuthenticatorMockRecorder {
 	return m.recorder
 }
 
+// CheckActive mocks base method.
+func (m *MockAuthenticator) CheckActive(arg0 context.Context, arg1 user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckActive", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckActive indicates an expected call of CheckActive.
+func (mr *MockAuthenticatorMockRecorder) CheckActive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckActive", reflect.TypeOf((*MockAuthenticator)(nil).CheckActive), arg0, arg1)
+}
+
 // Login mocks base method.
 func (m *MockAuthenticator) Login(arg0 context.Context, arg1, arg2 string) (user.User, error) {
 	m.ctrl.T.Helper()
@@ -52,12 +67,27 @@ func (mr *MockAuthenticatorMockRecorder) Login(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthenticator)(nil).Login), arg0, arg1, arg2)
 }
 
+// NormalizeLogin mocks base method.
+func (m *MockAuthenticator) NormalizeLogin(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizeLogin", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NormalizeLogin indicates an expected call of NormalizeLogin.
+func (mr *MockAuthenticatorMockRecorder) NormalizeLogin(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizeLogin", reflect.TypeOf((*MockAuthenticator)(nil).NormalizeLogin), arg0)
+}
+
 // SignIn mocks base method.
-func (m *MockAuthenticator) SignIn(arg0 context.Context, arg1, arg2 string) error {
+func (m *MockAuthenticator) SignIn(arg0 context.Context, arg1, arg2 string) (user.User, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SignIn", arg0, arg1, arg2)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // SignIn indicates an expected call of SignIn.
@@ -66,6 +96,313 @@ func (mr *MockAuthenticatorMockRecorder) SignIn(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIn", reflect.TypeOf((*MockAuthenticator)(nil).SignIn), arg0, arg1, arg2)
 }
 
+// MockPasswordManager is a mock of PasswordManager interface.
+type MockPasswordManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordManagerMockRecorder
+}
+
+// MockPasswordManagerMockRecorder is the mock recorder for MockPasswordManager.
+type MockPasswordManagerMockRecorder struct {
+	mock *MockPasswordManager
+}
+
+// NewMockPasswordManager creates a new mock instance.
+func NewMockPasswordManager(ctrl *gomock.Controller) *MockPasswordManager {
+	mock := &MockPasswordManager{ctrl: ctrl}
+	mock.recorder = &MockPasswordManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordManager) EXPECT() *MockPasswordManagerMockRecorder {
+	return m.recorder
+}
+
+// ChangePassword mocks base method.
+func (m *MockPasswordManager) ChangePassword(arg0 context.Context, arg1 user.User, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockPasswordManagerMockRecorder) ChangePassword(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockPasswordManager)(nil).ChangePassword), arg0, arg1, arg2, arg3)
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockPasswordManager) RequestPasswordReset(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockPasswordManagerMockRecorder) RequestPasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockPasswordManager)(nil).RequestPasswordReset), arg0, arg1)
+}
+
+// ResetPassword mocks base method.
+func (m *MockPasswordManager) ResetPassword(arg0 context.Context, arg1, arg2 string) (user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockPasswordManagerMockRecorder) ResetPassword(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockPasswordManager)(nil).ResetPassword), arg0, arg1, arg2)
+}
+
+// MockAccountManager is a mock of AccountManager interface.
+type MockAccountManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountManagerMockRecorder
+}
+
+// MockAccountManagerMockRecorder is the mock recorder for MockAccountManager.
+type MockAccountManagerMockRecorder struct {
+	mock *MockAccountManager
+}
+
+// NewMockAccountManager creates a new mock instance.
+func NewMockAccountManager(ctrl *gomock.Controller) *MockAccountManager {
+	mock := &MockAccountManager{ctrl: ctrl}
+	mock.recorder = &MockAccountManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountManager) EXPECT() *MockAccountManagerMockRecorder {
+	return m.recorder
+}
+
+// DeleteAccount mocks base method.
+func (m *MockAccountManager) DeleteAccount(arg0 context.Context, arg1 user.User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAccountManagerMockRecorder) DeleteAccount(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountManager)(nil).DeleteAccount), arg0, arg1, arg2)
+}
+
+// DeleteUser mocks base method.
+func (m *MockAccountManager) DeleteUser(arg0 context.Context, arg1 user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockAccountManagerMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockAccountManager)(nil).DeleteUser), arg0, arg1)
+}
+
+// DisableUser mocks base method.
+func (m *MockAccountManager) DisableUser(arg0 context.Context, arg1 user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockAccountManagerMockRecorder) DisableUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockAccountManager)(nil).DisableUser), arg0, arg1)
+}
+
+// EnableUser mocks base method.
+func (m *MockAccountManager) EnableUser(arg0 context.Context, arg1 user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableUser indicates an expected call of EnableUser.
+func (mr *MockAccountManagerMockRecorder) EnableUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUser", reflect.TypeOf((*MockAccountManager)(nil).EnableUser), arg0, arg1)
+}
+
+// MockRoleManager is a mock of RoleManager interface.
+type MockRoleManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleManagerMockRecorder
+}
+
+// MockRoleManagerMockRecorder is the mock recorder for MockRoleManager.
+type MockRoleManagerMockRecorder struct {
+	mock *MockRoleManager
+}
+
+// NewMockRoleManager creates a new mock instance.
+func NewMockRoleManager(ctrl *gomock.Controller) *MockRoleManager {
+	mock := &MockRoleManager{ctrl: ctrl}
+	mock.recorder = &MockRoleManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleManager) EXPECT() *MockRoleManagerMockRecorder {
+	return m.recorder
+}
+
+// ChangeRole mocks base method.
+func (m *MockRoleManager) ChangeRole(arg0 context.Context, arg1 user.User, arg2 user.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeRole indicates an expected call of ChangeRole.
+func (mr *MockRoleManagerMockRecorder) ChangeRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeRole", reflect.TypeOf((*MockRoleManager)(nil).ChangeRole), arg0, arg1, arg2)
+}
+
+// UserRole mocks base method.
+func (m *MockRoleManager) UserRole(arg0 context.Context, arg1 user.User) (user.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserRole", arg0, arg1)
+	ret0, _ := ret[0].(user.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserRole indicates an expected call of UserRole.
+func (mr *MockRoleManagerMockRecorder) UserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserRole", reflect.TypeOf((*MockRoleManager)(nil).UserRole), arg0, arg1)
+}
+
+// MockTwoFactorManager is a mock of TwoFactorManager interface.
+type MockTwoFactorManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorManagerMockRecorder
+}
+
+// MockTwoFactorManagerMockRecorder is the mock recorder for MockTwoFactorManager.
+type MockTwoFactorManagerMockRecorder struct {
+	mock *MockTwoFactorManager
+}
+
+// NewMockTwoFactorManager creates a new mock instance.
+func NewMockTwoFactorManager(ctrl *gomock.Controller) *MockTwoFactorManager {
+	mock := &MockTwoFactorManager{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorManager) EXPECT() *MockTwoFactorManagerMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTwoFactor mocks base method.
+func (m *MockTwoFactorManager) ConfirmTwoFactor(arg0 context.Context, arg1 user.User, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTwoFactor", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTwoFactor indicates an expected call of ConfirmTwoFactor.
+func (mr *MockTwoFactorManagerMockRecorder) ConfirmTwoFactor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTwoFactor", reflect.TypeOf((*MockTwoFactorManager)(nil).ConfirmTwoFactor), arg0, arg1, arg2)
+}
+
+// DisableTwoFactor mocks base method.
+func (m *MockTwoFactorManager) DisableTwoFactor(arg0 context.Context, arg1 user.User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTwoFactor", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTwoFactor indicates an expected call of DisableTwoFactor.
+func (mr *MockTwoFactorManagerMockRecorder) DisableTwoFactor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTwoFactor", reflect.TypeOf((*MockTwoFactorManager)(nil).DisableTwoFactor), arg0, arg1, arg2)
+}
+
+// IsTwoFactorEnabled mocks base method.
+func (m *MockTwoFactorManager) IsTwoFactorEnabled(arg0 context.Context, arg1 user.User) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTwoFactorEnabled", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTwoFactorEnabled indicates an expected call of IsTwoFactorEnabled.
+func (mr *MockTwoFactorManagerMockRecorder) IsTwoFactorEnabled(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTwoFactorEnabled", reflect.TypeOf((*MockTwoFactorManager)(nil).IsTwoFactorEnabled), arg0, arg1)
+}
+
+// SetupTwoFactor mocks base method.
+func (m *MockTwoFactorManager) SetupTwoFactor(arg0 context.Context, arg1 user.User) (app.TwoFactorSetup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetupTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].(app.TwoFactorSetup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetupTwoFactor indicates an expected call of SetupTwoFactor.
+func (mr *MockTwoFactorManagerMockRecorder) SetupTwoFactor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupTwoFactor", reflect.TypeOf((*MockTwoFactorManager)(nil).SetupTwoFactor), arg0, arg1)
+}
+
+// VerifyTOTP mocks base method.
+func (m *MockTwoFactorManager) VerifyTOTP(arg0 context.Context, arg1 user.User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTOTP", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyTOTP indicates an expected call of VerifyTOTP.
+func (mr *MockTwoFactorManagerMockRecorder) VerifyTOTP(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTOTP", reflect.TypeOf((*MockTwoFactorManager)(nil).VerifyTOTP), arg0, arg1, arg2)
+}
+
+// VerifyTwoFactor mocks base method.
+func (m *MockTwoFactorManager) VerifyTwoFactor(arg0 context.Context, arg1 user.User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactor", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyTwoFactor indicates an expected call of VerifyTwoFactor.
+func (mr *MockTwoFactorManagerMockRecorder) VerifyTwoFactor(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactor", reflect.TypeOf((*MockTwoFactorManager)(nil).VerifyTwoFactor), arg0, arg1, arg2)
+}
+
 // MockOrderProcessor is a mock of OrderProcessor interface.
 type MockOrderProcessor struct {
 	ctrl     *gomock.Controller
@@ -104,10 +441,10 @@ func (mr *MockOrderProcessorMockRecorder) Add(arg0, arg1, arg2 interface{}) *gom
 }
 
 // List mocks base method.
-func (m *MockOrderProcessor) List(arg0 context.Context, arg1 user.User) ([]order.Order, error) {
+func (m *MockOrderProcessor) List(arg0 context.Context, arg1 user.User) ([]entity.Order, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "List", arg0, arg1)
-	ret0, _ := ret[0].([]order.Order)
+	ret0, _ := ret[0].([]entity.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -142,10 +479,10 @@ func (m *MockBalanceGetter) EXPECT() *MockBalanceGetterMockRecorder {
 }
 
 // Get mocks base method.
-func (m *MockBalanceGetter) Get(arg0 context.Context, arg1 user.User) (order.Balance, error) {
+func (m *MockBalanceGetter) Get(arg0 context.Context, arg1 user.User) (entity.Balance, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", arg0, arg1)
-	ret0, _ := ret[0].(order.Balance)
+	ret0, _ := ret[0].(entity.Balance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -194,10 +531,10 @@ func (mr *MockWithdrawalProcessorMockRecorder) Add(arg0, arg1, arg2, arg3 interf
 }
 
 // List mocks base method.
-func (m *MockWithdrawalProcessor) List(arg0 context.Context, arg1 user.User) ([]order.Withdrawal, error) {
+func (m *MockWithdrawalProcessor) List(arg0 context.Context, arg1 user.User) ([]entity.Withdrawal, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "List", arg0, arg1)
-	ret0, _ := ret[0].([]order.Withdrawal)
+	ret0, _ := ret[0].([]entity.Withdrawal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -207,3 +544,56 @@ func (mr *MockWithdrawalProcessorMockRecorder) List(arg0, arg1 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWithdrawalProcessor)(nil).List), arg0, arg1)
 }
+
+// MockBackOffice is a mock of BackOffice interface.
+type MockBackOffice struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackOfficeMockRecorder
+}
+
+// MockBackOfficeMockRecorder is the mock recorder for MockBackOffice.
+type MockBackOfficeMockRecorder struct {
+	mock *MockBackOffice
+}
+
+// NewMockBackOffice creates a new mock instance.
+func NewMockBackOffice(ctrl *gomock.Controller) *MockBackOffice {
+	mock := &MockBackOffice{ctrl: ctrl}
+	mock.recorder = &MockBackOfficeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackOffice) EXPECT() *MockBackOfficeMockRecorder {
+	return m.recorder
+}
+
+// FindUsers mocks base method.
+func (m *MockBackOffice) FindUsers(arg0 context.Context, arg1 string, arg2 int) ([]entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUsers", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUsers indicates an expected call of FindUsers.
+func (mr *MockBackOfficeMockRecorder) FindUsers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUsers", reflect.TypeOf((*MockBackOffice)(nil).FindUsers), arg0, arg1, arg2)
+}
+
+// RecheckOrder mocks base method.
+func (m *MockBackOffice) RecheckOrder(arg0 context.Context, arg1 string) (entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecheckOrder", arg0, arg1)
+	ret0, _ := ret[0].(entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecheckOrder indicates an expected call of RecheckOrder.
+func (mr *MockBackOfficeMockRecorder) RecheckOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecheckOrder", reflect.TypeOf((*MockBackOffice)(nil).RecheckOrder), arg0, arg1)
+}
@@ -0,0 +1,31 @@
+package conf
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	adminKeyFlag = "admin-key"
+)
+
+var _ Configurer = (*Admin)(nil)
+
+// Admin административное API доступно пользователям с ролью администратора.
+// Ключ нужен, чтобы назначить первого администратора, после этого его лучше убрать
+type Admin struct {
+	Key Secret
+}
+
+func (a *Admin) SetPFlag(fs *pflag.FlagSet) {
+	fs.String(adminKeyFlag, "", "sets key for admin API in X-Admin-Key header, only admin role has access when empty")
+}
+
+func (a *Admin) Read() error {
+	a.Key = Secret(viper.GetString(adminKeyFlag))
+	return nil
+}
+
+func (a *Admin) AdminKeyEnabled() bool {
+	return a.Key != ""
+}
@@ -0,0 +1,105 @@
+package conf
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	authModeFlag           = "auth-mode"
+	authTokenAlgorithmFlag = "auth-token-algorithm"
+	authTokenKeysFlag      = "auth-token-keys"
+	authTokenTTLFlag       = "auth-token-ttl"
+	authTokenIssuerFlag    = "auth-token-issuer"
+	authRefreshTTLFlag     = "auth-refresh-token-ttl"
+)
+
+// AuthMode способ, которым пользователь подтверждает доступ к закрытым ресурсам
+type AuthMode string
+
+const (
+	AuthModeCookie AuthMode = "cookie"
+	AuthModeToken  AuthMode = "token"
+	AuthModeBoth   AuthMode = "both"
+)
+
+const (
+	TokenAlgorithmHS256 = "HS256"
+	TokenAlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrConfigAuthModeInvalid           = errors.New("auth mode must be one of: cookie, token, both")
+	ErrConfigAuthTokenAlgorithmInvalid = errors.New("auth token algorithm must be one of: HS256, EdDSA")
+	ErrConfigAuthTokenKeysNotSet       = errors.New("auth token keys must be set in token mode")
+	ErrConfigAuthTokenKeysFormat       = errors.New("auth token keys must be set as id:secret[,id:secret...]")
+	ErrConfigAuthTokenTTLInvalid       = errors.New("auth token ttl must be positive")
+	ErrConfigAuthRefreshTTLInvalid     = errors.New("auth refresh token ttl must not be less than token ttl")
+)
+var _ Configurer = (*Auth)(nil)
+
+type Auth struct {
+	Mode AuthMode
+	// TokenAlgorithm для HS256 секрет ключа - строка не короче 32 байт,
+	// для EdDSA - seed ключа Ed25519 в base64
+	TokenAlgorithm string
+	// TokenKeys первым ключом токены подписываются, остальными только проверяются
+	TokenKeys   []SigningKey
+	TokenTTL    time.Duration
+	TokenIssuer string
+	// RefreshTokenTTL время, в течение которого refresh-токен можно обменять на новую пару токенов
+	RefreshTokenTTL time.Duration
+}
+
+func (a *Auth) SetPFlag(fs *pflag.FlagSet) {
+	fs.String(authModeFlag, string(AuthModeCookie), "sets how users are authenticated: cookie, token, both")
+	fs.String(authTokenAlgorithmFlag, TokenAlgorithmHS256, "sets bearer token signing algorithm: HS256, EdDSA")
+	fs.String(authTokenKeysFlag, "", "sets bearer token signing keys as id:secret[,id:secret...], the first one signs")
+	fs.Duration(authTokenTTLFlag, time.Hour, "sets bearer token lifetime")
+	fs.String(authTokenIssuerFlag, "gophermart", "sets bearer token issuer")
+	fs.Duration(authRefreshTTLFlag, 30*24*time.Hour, "sets refresh token lifetime")
+}
+
+func (a *Auth) Read() (err error) {
+	var p problems
+	a.Mode = AuthMode(strings.ToLower(viper.GetString(authModeFlag)))
+	switch a.Mode {
+	case AuthModeCookie, AuthModeToken, AuthModeBoth:
+	default:
+		p.add(ErrConfigAuthModeInvalid)
+	}
+	a.TokenAlgorithm = viper.GetString(authTokenAlgorithmFlag)
+	if a.TokenAlgorithm != TokenAlgorithmHS256 && a.TokenAlgorithm != TokenAlgorithmEdDSA {
+		p.add(ErrConfigAuthTokenAlgorithmInvalid)
+	}
+	a.TokenKeys, err = parseSigningKeys(viper.GetString(authTokenKeysFlag))
+	if err != nil {
+		p.add(ErrConfigAuthTokenKeysFormat)
+	} else if a.UseToken() && len(a.TokenKeys) == 0 {
+		p.add(ErrConfigAuthTokenKeysNotSet)
+	}
+	a.TokenTTL = viper.GetDuration(authTokenTTLFlag)
+	if a.TokenTTL <= 0 {
+		p.add(ErrConfigAuthTokenTTLInvalid)
+	}
+	a.TokenIssuer = viper.GetString(authTokenIssuerFlag)
+	a.RefreshTokenTTL = viper.GetDuration(authRefreshTTLFlag)
+	if a.RefreshTokenTTL < a.TokenTTL {
+		p.add(ErrConfigAuthRefreshTTLInvalid)
+	}
+	return p.err()
+}
+
+// UseCookie пользователь получает сессионную куку
+func (a *Auth) UseCookie() bool {
+	return a.Mode == AuthModeCookie || a.Mode == AuthModeBoth
+}
+
+// UseToken пользователь получает bearer-токен
+func (a *Auth) UseToken() bool {
+	return a.Mode == AuthModeToken || a.Mode == AuthModeBoth
+}
@@ -1,7 +1,28 @@
 package conf
 
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const configFileFlag = "config"
+
+const secretFileSuffix = "_FILE"
+
+var (
+	ErrConfigFileKeyUnknown     = errors.New("config file has unknown key")
+	ErrConfigSecretFileConflict = errors.New("both variable and its _FILE variant are set")
+	ErrConfigSecretFileInvalid  = errors.New("can't read value from _FILE variable")
+)
+
 type Configurer interface {
-	SetPFlag()
+	SetPFlag(fs *pflag.FlagSet)
 	Read() error
 }
 
@@ -11,34 +32,137 @@ type App struct {
 	Server
 	Database
 	Externals
+	Session
+	Auth
+	Notifier
+	Admin
+	Throttle
+	Credentials
+	TwoFactor
 }
 
 func NewAppConfig() *App {
 	return &App{
-		Server:    Server{},
-		Database:  Database{},
-		Externals: Externals{},
+		Server:      Server{},
+		Database:    Database{},
+		Externals:   Externals{},
+		Session:     Session{},
+		Auth:        Auth{},
+		Notifier:    Notifier{},
+		Admin:       Admin{},
+		Throttle:    Throttle{},
+		Credentials: Credentials{},
+		TwoFactor:   TwoFactor{},
 	}
 }
 
-func (a *App) SetPFlag() {
-	a.Server.SetPFlag()
-	a.Database.SetPFlag()
-	a.Externals.SetPFlag()
+func (a *App) SetPFlag(fs *pflag.FlagSet) {
+	fs.StringP(configFileFlag, "c", "", "sets config file in YAML, TOML or JSON, its keys are flag names")
+	a.Server.SetPFlag(fs)
+	a.Database.SetPFlag(fs)
+	a.Externals.SetPFlag(fs)
+	a.Session.SetPFlag(fs)
+	a.Auth.SetPFlag(fs)
+	a.Notifier.SetPFlag(fs)
+	a.Admin.SetPFlag(fs)
+	a.Throttle.SetPFlag(fs)
+	a.Credentials.SetPFlag(fs)
+	a.TwoFactor.SetPFlag(fs)
 }
 
 func (a *App) Read() error {
-	err := a.Server.Read()
-	if err != nil {
-		return err
+	var p problems
+	for _, section := range a.sections() {
+		p.add(section.Read())
 	}
-	err = a.Database.Read()
-	if err != nil {
-		return err
+	return p.err()
+}
+
+func (a *App) sections() []Configurer {
+	return []Configurer{
+		&a.Server,
+		&a.Database,
+		&a.Externals,
+		&a.Session,
+		&a.Auth,
+		&a.Notifier,
+		&a.Admin,
+		&a.Throttle,
+		&a.Credentials,
+		&a.TwoFactor,
 	}
-	err = a.Externals.Read()
+}
+
+// Load общий для всех команд порядок чтения конфига. Приоритет значений: флаг, затем переменная окружения
+// (имя флага в верхнем регистре, "-" заменяется на "_"), затем файл из --config, затем значение по умолчанию.
+// Читаются и проверяются только переданные секции, чтобы, например, migrate не требовал адрес системы расчета.
+// Вместо переменной окружения можно задать NAME_FILE с путем к файлу, значение читается из него (секреты Docker/Kubernetes).
+// Проблемы всех секций возвращаются разом в *ValidationError.
+func Load(fs *pflag.FlagSet, sections ...Configurer) error {
+	err := viper.BindPFlags(fs)
 	if err != nil {
 		return err
 	}
-	return nil
+	viper.AutomaticEnv()
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+
+	var p problems
+	file := viper.GetString(configFileFlag)
+	if file != "" {
+		viper.SetConfigFile(file)
+		err = viper.ReadInConfig()
+		if err != nil {
+			return fmt.Errorf("can't read config file %s: %w", file, err)
+		}
+		for _, key := range unknownKeys(fs) {
+			p.add(fmt.Errorf("%w: %s in %s", ErrConfigFileKeyUnknown, key, file))
+		}
+	}
+	for _, err = range readSecretFiles(fs) {
+		p.add(err)
+	}
+	for _, section := range sections {
+		p.add(section.Read())
+	}
+	return p.err()
+}
+
+// readSecretFiles подставляет значения из файлов, указанных в NAME_FILE. Приоритет у них такой же, как у NAME:
+// флаг их перекрывает, а файл конфига и значение по умолчанию - нет
+func readSecretFiles(fs *pflag.FlagSet) (errs []error) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Name == configFileFlag {
+			return
+		}
+		env := strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		path, ok := os.LookupEnv(env + secretFileSuffix)
+		if !ok {
+			return
+		}
+		if _, ok = os.LookupEnv(env); ok {
+			errs = append(errs, fmt.Errorf("%w: %s and %s", ErrConfigSecretFileConflict, env, env+secretFileSuffix))
+			return
+		}
+		if f.Changed {
+			return
+		}
+		body, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w %s: %v", ErrConfigSecretFileInvalid, env+secretFileSuffix, err))
+			return
+		}
+		viper.Set(f.Name, strings.TrimRight(string(body), "\r\n"))
+	})
+	return errs
+}
+
+// unknownKeys ключи файла конфига, которым не соответствует ни один флаг, обычно это опечатки
+func unknownKeys(fs *pflag.FlagSet) (keys []string) {
+	for _, key := range viper.AllKeys() {
+		if fs.Lookup(key) == nil && viper.InConfig(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,76 @@
+package conf
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	loginMinLengthFlag     = "login-min-length"
+	loginMaxLengthFlag     = "login-max-length"
+	loginCharsetFlag       = "login-charset"
+	passwordMinLengthFlag  = "password-min-length"
+	passwordMaxLengthFlag  = "password-max-length"
+	passwordMinClassesFlag = "password-min-classes"
+	passwordDenylistFlag   = "password-denylist"
+)
+
+var (
+	ErrConfigLoginLengthInvalid     = errors.New("login min length must be positive and not greater than max length")
+	ErrConfigLoginCharsetInvalid    = errors.New("login charset must be valid regular expression")
+	ErrConfigPasswordLengthInvalid  = errors.New("password min length must be positive and not greater than max length")
+	ErrConfigPasswordClassesInvalid = errors.New("password min classes must be from 0 to 4")
+)
+var _ Configurer = (*Credentials)(nil)
+
+// Credentials политика логинов и паролей, проверяется при регистрации и смене пароля
+type Credentials struct {
+	LoginMinLength int
+	LoginMaxLength int
+	// LoginCharset регулярное выражение, которому должен соответствовать логин после нормализации
+	LoginCharset      string
+	PasswordMinLength int
+	PasswordMaxLength int
+	// PasswordMinClasses сколько классов символов (строчные, заглавные, цифры, прочие) должно быть в пароле
+	PasswordMinClasses int
+	// PasswordDenylist файл с паролями из утечек, по паролю на строку
+	PasswordDenylist string
+}
+
+func (c *Credentials) SetPFlag(fs *pflag.FlagSet) {
+	fs.Int(loginMinLengthFlag, 3, "sets min login length in characters")
+	fs.Int(loginMaxLengthFlag, 64, "sets max login length in characters")
+	fs.String(loginCharsetFlag, `^[\p{L}\p{N}._@+-]+$`, "sets regular expression for allowed login characters")
+	fs.Int(passwordMinLengthFlag, 8, "sets min password length in characters")
+	fs.Int(passwordMaxLengthFlag, 128, "sets max password length in characters")
+	fs.Int(passwordMinClassesFlag, 2, "sets how many of lowercase, uppercase, digits, other characters password must contain")
+	fs.String(passwordDenylistFlag, "", "sets file with breached passwords, one per line")
+}
+
+func (c *Credentials) Read() error {
+	var p problems
+	c.LoginMinLength = viper.GetInt(loginMinLengthFlag)
+	c.LoginMaxLength = viper.GetInt(loginMaxLengthFlag)
+	if c.LoginMinLength <= 0 || c.LoginMaxLength < c.LoginMinLength {
+		p.add(ErrConfigLoginLengthInvalid)
+	}
+	c.LoginCharset = viper.GetString(loginCharsetFlag)
+	_, err := regexp.Compile(c.LoginCharset)
+	if err != nil {
+		p.add(ErrConfigLoginCharsetInvalid)
+	}
+	c.PasswordMinLength = viper.GetInt(passwordMinLengthFlag)
+	c.PasswordMaxLength = viper.GetInt(passwordMaxLengthFlag)
+	if c.PasswordMinLength <= 0 || c.PasswordMaxLength < c.PasswordMinLength {
+		p.add(ErrConfigPasswordLengthInvalid)
+	}
+	c.PasswordMinClasses = viper.GetInt(passwordMinClassesFlag)
+	if c.PasswordMinClasses < 0 || c.PasswordMinClasses > 4 {
+		p.add(ErrConfigPasswordClassesInvalid)
+	}
+	c.PasswordDenylist = viper.GetString(passwordDenylistFlag)
+	return p.err()
+}
@@ -8,24 +8,30 @@ import (
 )
 
 const (
-	databaseFlag = "database-uri"
+	databaseFlag    = "database-uri"
+	autoMigrateFlag = "auto-migrate"
 )
 
 var ErrConfigDatabaseURINotSet = errors.New("connection string for DB is not set")
 var _ Configurer = (*Database)(nil)
 
 type Database struct {
-	URI string
+	URI SecretURI
+	// AutoMigrate накатывать миграции при старте сервера. Если выключено, сервер только проверяет,
+	// что схема актуальна, а миграции накатываются отдельно командой migrate
+	AutoMigrate bool
 }
 
-func (db *Database) SetPFlag() {
-	pflag.StringP(databaseFlag, "d", "", "sets connection string for DB")
+func (db *Database) SetPFlag(fs *pflag.FlagSet) {
+	fs.StringP(databaseFlag, "d", "", "sets connection string for DB")
+	fs.Bool(autoMigrateFlag, true, "sets whether server applies DB migrations on start")
 }
 
 func (db *Database) Read() error {
-	db.URI = viper.GetString(databaseFlag)
+	db.URI = SecretURI(viper.GetString(databaseFlag))
 	if db.URI == "" {
 		return ErrConfigDatabaseURINotSet
 	}
+	db.AutoMigrate = viper.GetBool(autoMigrateFlag)
 	return nil
 }
@@ -18,8 +18,8 @@ type Externals struct {
 	AccrualSystemAddress string
 }
 
-func (e *Externals) SetPFlag() {
-	pflag.StringP(accrualSystemFlag, "r", "", "sets accrual system address")
+func (e *Externals) SetPFlag(fs *pflag.FlagSet) {
+	fs.StringP(accrualSystemFlag, "r", "", "sets accrual system address")
 }
 
 func (e *Externals) Read() error {
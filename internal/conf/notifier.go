@@ -0,0 +1,48 @@
+package conf
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	notifierFlag     = "notifier"
+	notifierFileFlag = "notifier-file"
+)
+
+const (
+	NotifierLog  = "log"
+	NotifierFile = "file"
+)
+
+var (
+	ErrConfigNotifierInvalid    = errors.New("notifier must be one of: log, file")
+	ErrConfigNotifierFileNotSet = errors.New("notifier file is not set")
+)
+var _ Configurer = (*Notifier)(nil)
+
+// Notifier способ доставки пользователю токенов сброса пароля
+type Notifier struct {
+	Kind string
+	File string
+}
+
+func (n *Notifier) SetPFlag(fs *pflag.FlagSet) {
+	fs.String(notifierFlag, NotifierLog, "sets how password reset tokens are delivered: log, file")
+	fs.String(notifierFileFlag, "", "sets file for password reset tokens when notifier is file")
+}
+
+func (n *Notifier) Read() error {
+	var p problems
+	n.Kind = viper.GetString(notifierFlag)
+	if n.Kind != NotifierLog && n.Kind != NotifierFile {
+		p.add(ErrConfigNotifierInvalid)
+	}
+	n.File = viper.GetString(notifierFileFlag)
+	if n.Kind == NotifierFile && n.File == "" {
+		p.add(ErrConfigNotifierFileNotSet)
+	}
+	return p.err()
+}
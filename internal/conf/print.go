@@ -0,0 +1,73 @@
+package conf
+
+import (
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// sensitiveFlags значения этих флагов выводятся так же, как их секретные поля в конфиге
+var sensitiveFlags = map[string]func(string) string{
+	databaseFlag:          RedactURI,
+	authTokenKeysFlag:     redactSigningKeys,
+	sessionCookieKeysFlag: redactSigningKeys,
+	adminKeyFlag:          func(v string) string { return Secret(v).String() },
+}
+
+// Print выводит итоговые значения всех настроек после Load в формате YAML, который можно взять за основу файла конфига.
+// Секреты маскируются.
+func (a *App) Print(w io.Writer) error {
+	fs := pflag.NewFlagSet("print", pflag.ContinueOnError)
+	a.SetPFlag(fs)
+
+	values := make(map[string]interface{})
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Name == configFileFlag {
+			return
+		}
+		values[f.Name] = effectiveValue(f)
+	})
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	err := enc.Encode(values)
+	if err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// effectiveValue приводит значение к типу флага: viper возвращает строки для флагов и переменных окружения
+func effectiveValue(f *pflag.Flag) interface{} {
+	if redact, ok := sensitiveFlags[f.Name]; ok {
+		return redact(viper.GetString(f.Name))
+	}
+	switch f.Value.Type() {
+	case "bool":
+		return viper.GetBool(f.Name)
+	case "int":
+		return viper.GetInt(f.Name)
+	case "float64":
+		return viper.GetFloat64(f.Name)
+	case "duration":
+		return viper.GetDuration(f.Name).String()
+	default:
+		return viper.GetString(f.Name)
+	}
+}
+
+// redactSigningKeys оставляет видимыми только идентификаторы ключей
+func redactSigningKeys(str string) string {
+	keys, err := parseSigningKeys(str)
+	if err != nil {
+		return Secret(str).String()
+	}
+	redacted := make([]string, 0, len(keys))
+	for _, k := range keys {
+		redacted = append(redacted, k.String())
+	}
+	return strings.Join(redacted, ",")
+}
@@ -2,30 +2,72 @@ package conf
 
 import (
 	"errors"
+	"fmt"
+	"net"
+	"strings"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 const (
-	runAddressFlag = "run-address"
+	runAddressFlag     = "run-address"
+	trustedProxiesFlag = "trusted-proxies"
 )
 
-var ErrConfigRunAddressNotSet = errors.New("server address is not set")
+var (
+	ErrConfigRunAddressNotSet    = errors.New("server address is not set")
+	ErrConfigTrustedProxyInvalid = errors.New("trusted proxy must be an IP address or CIDR")
+)
 var _ Configurer = (*Server)(nil)
 
 type Server struct {
 	RunAddress string
+	// TrustedProxies только от этих адресов принимаются X-Forwarded-For и X-Real-IP,
+	// иначе клиент подменил бы свой IP и обошел блокировку по IP
+	TrustedProxies []*net.IPNet
 }
 
-func (s *Server) SetPFlag() {
-	pflag.StringP(runAddressFlag, "a", ":8080", "sets http server address")
+func (s *Server) SetPFlag(fs *pflag.FlagSet) {
+	fs.StringP(runAddressFlag, "a", ":8080", "sets http server address")
+	fs.String(trustedProxiesFlag, "", "sets comma separated IPs or CIDRs of reverse proxies allowed to pass client IP")
 }
 
 func (s *Server) Read() error {
+	var p problems
 	s.RunAddress = viper.GetString(runAddressFlag)
 	if s.RunAddress == "" {
-		return ErrConfigRunAddressNotSet
+		p.add(ErrConfigRunAddressNotSet)
+	}
+	var err error
+	s.TrustedProxies, err = parseNets(viper.GetString(trustedProxiesFlag))
+	p.add(err)
+	return p.err()
+}
+
+// parseNets отдельный IP считается сетью из одного адреса
+func parseNets(list string) (nets []*net.IPNet, err error) {
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("%w: %s", ErrConfigTrustedProxyInvalid, item)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			item += fmt.Sprintf("/%d", bits)
+		}
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrConfigTrustedProxyInvalid, item)
+		}
+		nets = append(nets, ipNet)
 	}
-	return nil
+	return nets, nil
 }
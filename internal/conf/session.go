@@ -0,0 +1,127 @@
+package conf
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	sessionIdleTTLFlag        = "session-idle-ttl"
+	sessionLifetimeFlag       = "session-lifetime"
+	sessionCookieSecureFlag   = "session-cookie-secure"
+	sessionCookieHTTPOnlyFlag = "session-cookie-http-only"
+	sessionCookieSameSiteFlag = "session-cookie-same-site"
+	sessionCookieDomainFlag   = "session-cookie-domain"
+	sessionCookieKeysFlag     = "session-cookie-keys"
+)
+
+var (
+	ErrConfigSessionIdleTTLInvalid   = errors.New("session idle ttl must be positive")
+	ErrConfigSessionLifetimeInvalid  = errors.New("session lifetime must not be less than idle ttl")
+	ErrConfigSessionSameSiteInvalid  = errors.New("session cookie SameSite must be one of: default, lax, strict, none")
+	ErrConfigSessionSameSiteInsecure = errors.New("session cookie with SameSite=None must be secure")
+	ErrConfigSessionCookieKeysFormat = errors.New("session cookie keys must be set as id:secret[,id:secret...]")
+)
+var _ Configurer = (*Session)(nil)
+
+type Session struct {
+	// IdleTTL время, через которое неиспользуемая сессия истекает
+	IdleTTL time.Duration
+	// Lifetime время, через которое сессия истекает независимо от активности пользователя
+	Lifetime       time.Duration
+	CookieSecure   bool
+	CookieHTTPOnly bool
+	CookieSameSite http.SameSite
+	CookieDomain   string
+	// CookieKeys ключи подписи кук. Первым ключом куки подписываются, остальными только проверяются.
+	// Если ключи не заданы, то при старте генерируется случайный ключ.
+	CookieKeys []SigningKey
+}
+
+// SigningKey ключ подписи с идентификатором, по которому его можно найти при проверке
+type SigningKey struct {
+	ID     string
+	Secret Secret
+}
+
+func (k SigningKey) String() string {
+	return k.ID + ":" + k.Secret.String()
+}
+
+func (s *Session) SetPFlag(fs *pflag.FlagSet) {
+	fs.Duration(sessionIdleTTLFlag, 30*time.Minute, "sets session idle timeout")
+	fs.Duration(sessionLifetimeFlag, 24*time.Hour, "sets session absolute lifetime")
+	fs.Bool(sessionCookieSecureFlag, false, "sends session cookie over HTTPS only")
+	fs.Bool(sessionCookieHTTPOnlyFlag, true, "hides session cookie from JavaScript")
+	fs.String(sessionCookieSameSiteFlag, "lax", "sets session cookie SameSite attribute: default, lax, strict, none")
+	fs.String(sessionCookieDomainFlag, "", "sets session cookie domain")
+	fs.String(sessionCookieKeysFlag, "", "sets session cookie signing keys as id:secret[,id:secret...], the first one signs")
+}
+
+func (s *Session) Read() (err error) {
+	var p problems
+	s.IdleTTL = viper.GetDuration(sessionIdleTTLFlag)
+	if s.IdleTTL <= 0 {
+		p.add(ErrConfigSessionIdleTTLInvalid)
+	}
+	s.Lifetime = viper.GetDuration(sessionLifetimeFlag)
+	if s.Lifetime < s.IdleTTL {
+		p.add(ErrConfigSessionLifetimeInvalid)
+	}
+	s.CookieSecure = viper.GetBool(sessionCookieSecureFlag)
+	s.CookieHTTPOnly = viper.GetBool(sessionCookieHTTPOnlyFlag)
+	s.CookieSameSite, err = parseSameSite(viper.GetString(sessionCookieSameSiteFlag))
+	if err != nil {
+		p.add(err)
+	} else if s.CookieSameSite == http.SameSiteNoneMode && !s.CookieSecure {
+		p.add(ErrConfigSessionSameSiteInsecure)
+	}
+	s.CookieDomain = viper.GetString(sessionCookieDomainFlag)
+	s.CookieKeys, err = parseSigningKeys(viper.GetString(sessionCookieKeysFlag))
+	if err != nil {
+		p.add(ErrConfigSessionCookieKeysFormat)
+	}
+	return p.err()
+}
+
+var errSigningKeysFormat = errors.New("signing keys must be set as id:secret[,id:secret...] with unique ids")
+
+// parseSigningKeys разбирает строку вида id:secret[,id:secret...], порядок ключей сохраняется
+func parseSigningKeys(str string) (keys []SigningKey, err error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+	ids := make(map[string]struct{})
+	for _, pair := range strings.Split(str, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, errSigningKeysFormat
+		}
+		if _, ok := ids[kv[0]]; ok {
+			return nil, errSigningKeysFormat
+		}
+		ids[kv[0]] = struct{}{}
+		keys = append(keys, SigningKey{ID: kv[0], Secret: Secret(kv[1])})
+	}
+	return keys, nil
+}
+
+func parseSameSite(str string) (http.SameSite, error) {
+	switch strings.ToLower(strings.TrimSpace(str)) {
+	case "default", "":
+		return http.SameSiteDefaultMode, nil
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, ErrConfigSessionSameSiteInvalid
+	}
+}
@@ -0,0 +1,76 @@
+package conf
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	throttleStoreFlag         = "throttle-store"
+	throttleLoginAttemptsFlag = "throttle-login-attempts"
+	throttleIPAttemptsFlag    = "throttle-ip-attempts"
+	throttleWindowFlag        = "throttle-window"
+	throttleBaseDelayFlag     = "throttle-base-delay"
+	throttleMaxDelayFlag      = "throttle-max-delay"
+)
+
+const (
+	ThrottleStoreMemory   = "memory"
+	ThrottleStorePostgres = "postgres"
+)
+
+var (
+	ErrConfigThrottleStoreInvalid    = errors.New("throttle store must be one of: memory, postgres")
+	ErrConfigThrottleAttemptsInvalid = errors.New("throttle attempts must be positive")
+	ErrConfigThrottleWindowInvalid   = errors.New("throttle window must be positive")
+	ErrConfigThrottleDelayInvalid    = errors.New("throttle base delay must be positive and not greater than max delay")
+)
+var _ Configurer = (*Throttle)(nil)
+
+// Throttle защита от подбора паролей. Неудачные попытки входа считаются по логину и по IP клиента,
+// после исчерпания лимита блокировка начинается с BaseDelay и удваивается с каждой неудачей до MaxDelay.
+type Throttle struct {
+	// Store для нескольких экземпляров сервиса счетчики нужно хранить в postgres
+	Store         string
+	LoginAttempts int
+	IPAttempts    int
+	// Window время, через которое счетчик неудач сбрасывается
+	Window    time.Duration
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func (t *Throttle) SetPFlag(fs *pflag.FlagSet) {
+	fs.String(throttleStoreFlag, ThrottleStoreMemory, "sets where failed login counters are kept: memory, postgres")
+	fs.Int(throttleLoginAttemptsFlag, 5, "sets failed attempts per login before lockout")
+	fs.Int(throttleIPAttemptsFlag, 20, "sets failed attempts per client IP before lockout")
+	fs.Duration(throttleWindowFlag, 15*time.Minute, "sets time after which failed attempts are forgotten")
+	fs.Duration(throttleBaseDelayFlag, time.Second, "sets first lockout, it doubles on each next failure")
+	fs.Duration(throttleMaxDelayFlag, 15*time.Minute, "sets longest lockout")
+}
+
+func (t *Throttle) Read() error {
+	var p problems
+	t.Store = viper.GetString(throttleStoreFlag)
+	if t.Store != ThrottleStoreMemory && t.Store != ThrottleStorePostgres {
+		p.add(ErrConfigThrottleStoreInvalid)
+	}
+	t.LoginAttempts = viper.GetInt(throttleLoginAttemptsFlag)
+	t.IPAttempts = viper.GetInt(throttleIPAttemptsFlag)
+	if t.LoginAttempts <= 0 || t.IPAttempts <= 0 {
+		p.add(ErrConfigThrottleAttemptsInvalid)
+	}
+	t.Window = viper.GetDuration(throttleWindowFlag)
+	if t.Window <= 0 {
+		p.add(ErrConfigThrottleWindowInvalid)
+	}
+	t.BaseDelay = viper.GetDuration(throttleBaseDelayFlag)
+	t.MaxDelay = viper.GetDuration(throttleMaxDelayFlag)
+	if t.BaseDelay <= 0 || t.MaxDelay < t.BaseDelay {
+		p.add(ErrConfigThrottleDelayInvalid)
+	}
+	return p.err()
+}
@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	totpIssuerFlag            = "totp-issuer"
+	totpChallengeTTLFlag      = "totp-challenge-ttl"
+	totpWithdrawThresholdFlag = "totp-withdraw-threshold"
+)
+
+var (
+	ErrConfigTOTPIssuerNotSet          = errors.New("totp issuer is not set")
+	ErrConfigTOTPChallengeTTLInvalid   = errors.New("totp challenge ttl must be positive")
+	ErrConfigTOTPWithdrawThresholdSign = errors.New("totp withdraw threshold must not be negative")
+)
+var _ Configurer = (*TwoFactor)(nil)
+
+// TwoFactor второй фактор по TOTP, включает его сам пользователь
+type TwoFactor struct {
+	// Issuer название сервиса в приложении-аутентификаторе
+	Issuer string
+	// ChallengeTTL сколько времени после ввода пароля дается на ввод кода
+	ChallengeTTL time.Duration
+	// WithdrawThreshold списания больше этой суммы пользователь с 2FA подтверждает кодом
+	WithdrawThreshold float64
+}
+
+func (tf *TwoFactor) SetPFlag(fs *pflag.FlagSet) {
+	fs.String(totpIssuerFlag, "GopherMart", "sets service name shown in authenticator apps")
+	fs.Duration(totpChallengeTTLFlag, 5*time.Minute, "sets time to enter two-factor code after password")
+	fs.Float64(totpWithdrawThresholdFlag, 1000, "sets withdrawal sum above which two-factor code is required")
+}
+
+func (tf *TwoFactor) Read() error {
+	var p problems
+	tf.Issuer = viper.GetString(totpIssuerFlag)
+	if tf.Issuer == "" {
+		p.add(ErrConfigTOTPIssuerNotSet)
+	}
+	tf.ChallengeTTL = viper.GetDuration(totpChallengeTTLFlag)
+	if tf.ChallengeTTL <= 0 {
+		p.add(ErrConfigTOTPChallengeTTLInvalid)
+	}
+	tf.WithdrawThreshold = viper.GetFloat64(totpWithdrawThresholdFlag)
+	if tf.WithdrawThreshold < 0 {
+		p.add(ErrConfigTOTPWithdrawThresholdSign)
+	}
+	return p.err()
+}
@@ -0,0 +1,51 @@
+package conf
+
+import (
+	"errors"
+	"strings"
+)
+
+// ValidationError все проблемы конфига, найденные за один проход, чтобы их можно было исправить разом.
+// errors.Is находит среди них любую из ErrConfig...
+type ValidationError struct {
+	Errs []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		msgs = append(msgs, err.Error())
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// problems накапливает ошибки проверки секций, вложенные ValidationError раскрываются
+type problems []error
+
+func (p *problems) add(err error) {
+	if err == nil {
+		return
+	}
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		*p = append(*p, verr.Errs...)
+		return
+	}
+	*p = append(*p, err)
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Errs: p}
+}
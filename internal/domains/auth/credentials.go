@@ -2,22 +2,37 @@ package auth
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
-	"hash"
+	"sync"
 
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
 	_ "github.com/golang/mock/mockgen/model"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
 )
 
-const salt = "gPhRmRt"
-
 //go:generate mockgen -destination=./mocks/mock_credentials.go . Repository
 
 type Repository interface {
 	Create(ctx context.Context, user user.User, login, pword string) error
 	Read(ctx context.Context, login string) (usr user.User, err error)
-	ReadWithPassword(ctx context.Context, login, pword string) (usr user.User, err error)
+	// ReadPasswordHash возвращает пользователя с его статусом и закодированный хеш его пароля.
+	// Если логина нет - возвращает errors.ErrPairLoginPwordIsNotExist
+	ReadPasswordHash(ctx context.Context, login string) (usr user.User, hash string, err error)
+	// ReadUserPasswordHash возвращает закодированный хеш пароля пользователя.
+	// Если у пользователя нет кредов - возвращает errors.ErrPairLoginPwordIsNotExist
+	ReadUserPasswordHash(ctx context.Context, usr user.User) (hash string, err error)
+	// ReadLogin возвращает нормализованный логин пользователя.
+	// Если у пользователя нет кредов - возвращает errors.ErrPairLoginPwordIsNotExist
+	ReadLogin(ctx context.Context, usr user.User) (login string, err error)
+	// UpdatePasswordHash если у пользователя нет кредов - возвращает errors.ErrPairLoginPwordIsNotExist
+	UpdatePasswordHash(ctx context.Context, usr user.User, hash string) error
+	// ReadStatus если пользователя нет - возвращает errors.ErrUserNotFound
+	ReadStatus(ctx context.Context, usr user.User) (status user.Status, err error)
+	// UpdateStatus если пользователя нет или он удален - возвращает errors.ErrUserNotFound
+	UpdateStatus(ctx context.Context, usr user.User, status user.Status) error
+	// Delete удаляет креды пользователя
+	Delete(ctx context.Context, usr user.User) error
 }
 
 var _ CredentialManager = (*Manager)(nil)
@@ -27,57 +42,187 @@ var _ CredentialManager = (*Manager)(nil)
 // Имеет смысл кешировать столько с очень большой нагрузкой по аутентификации.
 type Manager struct {
 	repo   Repository
-	hasher *SaltedHash
+	hasher PasswordHasher
+	policy *CredentialPolicy
+	// dummy хеш для проверки пароля несуществующего логина,
+	// чтобы по времени ответа нельзя было понять, что логина нет
+	dummyOnce sync.Once
+	dummy     string
 }
 
 func NewManager(repo Repository) *Manager {
+	return NewManagerWithHasher(repo, NewDefaultHasher())
+}
+
+func NewManagerWithHasher(repo Repository, hasher PasswordHasher) *Manager {
+	return NewManagerWithPolicy(repo, hasher, NewDefaultCredentialPolicy())
+}
+
+func NewManagerWithPolicy(repo Repository, hasher PasswordHasher, policy *CredentialPolicy) *Manager {
 	if repo == nil {
 		panic("missing Repository, parameter must not be nil")
 	}
-	return &Manager{repo: repo, hasher: NewSaltedHashWithDefaultMixer(sha256.New(), []byte(salt))}
+	if hasher == nil {
+		panic("missing PasswordHasher, parameter must not be nil")
+	}
+	if policy == nil {
+		panic("missing *CredentialPolicy, parameter must not be nil")
+	}
+	return &Manager{repo: repo, hasher: hasher, policy: policy}
 }
 
+// AddNewUser логин сохраняется нормализованным. Если креды не проходят политику - возвращает *errors.PolicyViolation
 func (man *Manager) AddNewUser(ctx context.Context, usr user.User, login, pword string) error {
-	return man.repo.Create(ctx, usr, login, man.getHashedPassword(pword))
+	login = man.policy.NormalizeLogin(login)
+	err := man.policy.ValidateLogin(login)
+	if err != nil {
+		return err
+	}
+	err = man.policy.ValidatePassword(pword, login)
+	if err != nil {
+		return err
+	}
+	hash, err := man.hasher.Hash(pword)
+	if err != nil {
+		return err
+	}
+	return man.repo.Create(ctx, usr, login, hash)
 }
 
 func (man *Manager) GetUser(ctx context.Context, login string) (usr user.User, err error) {
-	usr, err = man.repo.Read(ctx, login)
+	usr, err = man.repo.Read(ctx, man.policy.NormalizeLogin(login))
 	if err != nil {
 		return user.User{}, err
 	}
 	return usr, nil
 }
 
+func (man *Manager) NormalizeLogin(login string) string {
+	return man.policy.NormalizeLogin(login)
+}
+
+// AuthenticateUser проверяет пароль по хешу из хранилища.
+// Если хеш сделан устаревшим алгоритмом или с устаревшими параметрами, то пароль перехешируется.
 func (man *Manager) AuthenticateUser(ctx context.Context, login, pword string) (usr user.User, err error) {
-	usr, err = man.repo.ReadWithPassword(ctx, login, man.getHashedPassword(pword))
+	usr, hash, err := man.repo.ReadPasswordHash(ctx, man.policy.NormalizeLogin(login))
 	if err != nil {
+		if errors.Is(err, errors2.ErrPairLoginPwordIsNotExist) {
+			_, _ = man.hasher.Verify(pword, man.dummyHash())
+		}
 		return user.User{}, err
 	}
+
+	ok, err := man.hasher.Verify(pword, hash)
+	if err != nil {
+		return user.User{}, err
+	}
+	if !ok {
+		return user.User{}, errors2.ErrPairLoginPwordIsNotExist
+	}
+
+	// статус проверяется только после пароля, чтобы не раскрывать состояние учетной записи посторонним
+	if usr.Status == user.Disabled {
+		return user.User{}, errors2.ErrUserIsDisabled
+	}
+
+	if man.hasher.NeedsRehash(hash) {
+		man.rehash(ctx, usr, pword)
+	}
 	return usr, nil
 }
 
-func (man *Manager) getHashedPassword(pword string) string {
-	return base64.URLEncoding.EncodeToString(man.hasher.Sum([]byte(pword)))
+func (man *Manager) CheckActive(ctx context.Context, usr user.User) error {
+	status, err := man.repo.ReadStatus(ctx, usr)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case user.Disabled:
+		return errors2.ErrUserIsDisabled
+	case user.Deleted:
+		return errors2.ErrUserNotFound
+	}
+	return nil
 }
 
-type SaltedHash struct {
-	hash.Hash
-	salt  []byte
-	mixer func([]byte, []byte) []byte
+// ChangePassword меняет пароль пользователя, если он подтвердил старый пароль
+func (man *Manager) ChangePassword(ctx context.Context, usr user.User, oldPword, newPword string) error {
+	err := man.VerifyPassword(ctx, usr, oldPword)
+	if err != nil {
+		return err
+	}
+	return man.SetPassword(ctx, usr, newPword)
+}
+
+// VerifyPassword проверяет пароль уже аутентифицированного пользователя перед опасными действиями
+func (man *Manager) VerifyPassword(ctx context.Context, usr user.User, pword string) error {
+	hash, err := man.repo.ReadUserPasswordHash(ctx, usr)
+	if err != nil {
+		return err
+	}
+	ok, err := man.hasher.Verify(pword, hash)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors2.ErrPasswordIsIncorrect
+	}
+	return nil
+}
+
+// DisableUser запрещает пользователю вход. Креды сохраняются, чтобы учетную запись можно было включить обратно.
+func (man *Manager) DisableUser(ctx context.Context, usr user.User) error {
+	return man.repo.UpdateStatus(ctx, usr, user.Disabled)
+}
+
+func (man *Manager) EnableUser(ctx context.Context, usr user.User) error {
+	return man.repo.UpdateStatus(ctx, usr, user.Active)
 }
 
-func NewSaltedHash(h hash.Hash, salt []byte, mixer func([]byte, []byte) []byte) *SaltedHash {
-	return &SaltedHash{Hash: h, salt: salt, mixer: mixer}
+// DeleteUser удаляет креды пользователя, вместе с ними удаляется и логин - единственные персональные данные.
+// Сам пользователь остается, чтобы на него продолжали ссылаться заказы и списания.
+func (man *Manager) DeleteUser(ctx context.Context, usr user.User) error {
+	err := man.repo.UpdateStatus(ctx, usr, user.Deleted)
+	if err != nil {
+		return err
+	}
+	return man.repo.Delete(ctx, usr)
 }
 
-func NewSaltedHashWithDefaultMixer(h hash.Hash, salt []byte) *SaltedHash {
-	m := func(body []byte, salt []byte) []byte {
-		return append(body, salt...)
+// SetPassword меняет пароль пользователя без проверки старого, например, при сбросе пароля.
+// Если пароль не проходит политику - возвращает *errors.PolicyViolation
+func (man *Manager) SetPassword(ctx context.Context, usr user.User, pword string) error {
+	login, err := man.repo.ReadLogin(ctx, usr)
+	if err != nil {
+		return err
+	}
+	err = man.policy.ValidatePassword(pword, login)
+	if err != nil {
+		return err
+	}
+	hash, err := man.hasher.Hash(pword)
+	if err != nil {
+		return err
+	}
+	return man.repo.UpdatePasswordHash(ctx, usr, hash)
+}
+
+// rehash не прерывает аутентификацию при ошибке - пароль будет перехеширован при следующем входе
+func (man *Manager) rehash(ctx context.Context, usr user.User, pword string) {
+	hash, err := man.hasher.Hash(pword)
+	if err != nil {
+		log.Error().Err(err).Msgf("can't rehash password of user %s", usr.ID)
+		return
+	}
+	err = man.repo.UpdatePasswordHash(ctx, usr, hash)
+	if err != nil {
+		log.Error().Err(err).Msgf("can't update password hash of user %s", usr.ID)
 	}
-	return NewSaltedHash(h, salt, m)
 }
 
-func (h SaltedHash) Sum(b []byte) []byte {
-	return h.Hash.Sum(h.mixer(b, h.salt))
+func (man *Manager) dummyHash() string {
+	man.dummyOnce.Do(func() {
+		man.dummy, _ = man.hasher.Hash("dummy password")
+	})
+	return man.dummy
 }
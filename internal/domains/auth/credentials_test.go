@@ -0,0 +1,344 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	mock_auth "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_AddNewUser(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		login   string
+		pword   string
+		prepare func(repo *mock_auth.MockRepository)
+		wantErr error
+	}{
+		{
+			name:    "empty login",
+			login:   "",
+			pword:   testNewPassword,
+			prepare: func(repo *mock_auth.MockRepository) {},
+			wantErr: errors2.ErrCredentialPolicyViolation,
+		},
+		{
+			name:    "weak password",
+			login:   "bob",
+			pword:   "password",
+			prepare: func(repo *mock_auth.MockRepository) {},
+			wantErr: errors2.ErrCredentialPolicyViolation,
+		},
+		{
+			name:  "login is stored normalized",
+			login: "Bob",
+			pword: testNewPassword,
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().Create(gomock.Any(), usr, "bob", gomock.Any()).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			repo := mock_auth.NewMockRepository(mockCtrl)
+			tt.prepare(repo)
+
+			man := NewManagerWithHasher(repo, NewArgon2idHasher(testArgon2idParams))
+			err := man.AddNewUser(context.Background(), usr, tt.login, tt.pword)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestManager_AuthenticateUser(t *testing.T) {
+	usr := user.User{ID: "1"}
+	bcrypter := NewBcryptHasher(testBcryptCost)
+	hasher := NewUpgradeableHasher(NewArgon2idHasher(testArgon2idParams), bcrypter)
+	current, err := hasher.Hash("test")
+	require.NoError(t, err)
+	outdated, err := bcrypter.Hash("test")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		pword   string
+		prepare func(repo *mock_auth.MockRepository)
+		wantErr error
+	}{
+		{
+			name:  "unknown login",
+			pword: "test",
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().ReadPasswordHash(gomock.Any(), "test").Return(user.User{}, "", errors2.ErrPairLoginPwordIsNotExist)
+			},
+			wantErr: errors2.ErrPairLoginPwordIsNotExist,
+		},
+		{
+			name:  "wrong password",
+			pword: "wrong",
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().ReadPasswordHash(gomock.Any(), "test").Return(usr, current, nil)
+			},
+			wantErr: errors2.ErrPairLoginPwordIsNotExist,
+		},
+		{
+			name:  "disabled user",
+			pword: "test",
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().ReadPasswordHash(gomock.Any(), "test").Return(user.User{ID: "1", Status: user.Disabled}, current, nil)
+			},
+			wantErr: errors2.ErrUserIsDisabled,
+		},
+		{
+			name:  "up to date hash",
+			pword: "test",
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().ReadPasswordHash(gomock.Any(), "test").Return(usr, current, nil)
+			},
+		},
+		{
+			name:  "outdated hash is upgraded",
+			pword: "test",
+			prepare: func(repo *mock_auth.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().ReadPasswordHash(gomock.Any(), "test").Return(usr, outdated, nil),
+					repo.EXPECT().UpdatePasswordHash(gomock.Any(), usr, gomock.Any()).DoAndReturn(
+						func(_ context.Context, _ user.User, hash string) error {
+							assert.True(t, NewArgon2idHasher(testArgon2idParams).CanVerify(hash))
+							return nil
+						}),
+				)
+			},
+		},
+		{
+			name:  "failed upgrade doesn't break login",
+			pword: "test",
+			prepare: func(repo *mock_auth.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().ReadPasswordHash(gomock.Any(), "test").Return(usr, outdated, nil),
+					repo.EXPECT().UpdatePasswordHash(gomock.Any(), usr, gomock.Any()).Return(errDummy),
+				)
+			},
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			repo := mock_auth.NewMockRepository(mockCtrl)
+			tt.prepare(repo)
+
+			man := NewManagerWithHasher(repo, hasher)
+			got, err := man.AuthenticateUser(context.Background(), "test", tt.pword)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, usr, got)
+		})
+	}
+}
+
+func TestManager_ChangePassword(t *testing.T) {
+	usr := user.User{ID: "1"}
+	hasher := NewArgon2idHasher(testArgon2idParams)
+	current, err := hasher.Hash("old")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		oldPwd  string
+		prepare func(repo *mock_auth.MockRepository)
+		wantErr error
+	}{
+		{
+			name:   "no credentials",
+			oldPwd: "old",
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().ReadUserPasswordHash(gomock.Any(), usr).Return("", errors2.ErrPairLoginPwordIsNotExist)
+			},
+			wantErr: errors2.ErrPairLoginPwordIsNotExist,
+		},
+		{
+			name:   "wrong old password",
+			oldPwd: "wrong",
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().ReadUserPasswordHash(gomock.Any(), usr).Return(current, nil)
+			},
+			wantErr: errors2.ErrPasswordIsIncorrect,
+		},
+		{
+			name:   "password is changed",
+			oldPwd: "old",
+			prepare: func(repo *mock_auth.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().ReadUserPasswordHash(gomock.Any(), usr).Return(current, nil),
+					repo.EXPECT().ReadLogin(gomock.Any(), usr).Return("bob", nil),
+					repo.EXPECT().UpdatePasswordHash(gomock.Any(), usr, gomock.Any()).DoAndReturn(
+						func(_ context.Context, _ user.User, hash string) error {
+							ok, err := hasher.Verify(testNewPassword, hash)
+							assert.NoError(t, err)
+							assert.True(t, ok)
+							return nil
+						}),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			repo := mock_auth.NewMockRepository(mockCtrl)
+			tt.prepare(repo)
+
+			man := NewManagerWithHasher(repo, hasher)
+			err := man.ChangePassword(context.Background(), usr, tt.oldPwd, testNewPassword)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestManager_SetPassword(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		pword   string
+		prepare func(repo *mock_auth.MockRepository)
+		wantErr error
+	}{
+		{
+			name:  "no credentials",
+			pword: testNewPassword,
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().ReadLogin(gomock.Any(), usr).Return("", errors2.ErrPairLoginPwordIsNotExist)
+			},
+			wantErr: errors2.ErrPairLoginPwordIsNotExist,
+		},
+		{
+			name:  "password matches login",
+			pword: "Bob.Smith1",
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().ReadLogin(gomock.Any(), usr).Return("bob.smith1", nil)
+			},
+			wantErr: errors2.ErrCredentialPolicyViolation,
+		},
+		{
+			name:  "password is set",
+			pword: testNewPassword,
+			prepare: func(repo *mock_auth.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().ReadLogin(gomock.Any(), usr).Return("bob.smith1", nil),
+					repo.EXPECT().UpdatePasswordHash(gomock.Any(), usr, gomock.Any()).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			repo := mock_auth.NewMockRepository(mockCtrl)
+			tt.prepare(repo)
+
+			man := NewManagerWithHasher(repo, NewArgon2idHasher(testArgon2idParams))
+			err := man.SetPassword(context.Background(), usr, tt.pword)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestManager_CheckActive(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		status  user.Status
+		err     error
+		wantErr error
+	}{
+		{name: "active", status: user.Active},
+		{name: "disabled", status: user.Disabled, wantErr: errors2.ErrUserIsDisabled},
+		{name: "deleted", status: user.Deleted, wantErr: errors2.ErrUserNotFound},
+		{name: "not found", err: errors2.ErrUserNotFound, wantErr: errors2.ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			repo := mock_auth.NewMockRepository(mockCtrl)
+			repo.EXPECT().ReadStatus(gomock.Any(), usr).Return(tt.status, tt.err)
+
+			err := NewManager(repo).CheckActive(context.Background(), usr)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestManager_DeleteUser(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		prepare func(repo *mock_auth.MockRepository)
+		wantErr error
+	}{
+		{
+			name: "user is not found",
+			prepare: func(repo *mock_auth.MockRepository) {
+				repo.EXPECT().UpdateStatus(gomock.Any(), usr, user.Deleted).Return(errors2.ErrUserNotFound)
+			},
+			wantErr: errors2.ErrUserNotFound,
+		},
+		{
+			name: "can't delete credentials",
+			prepare: func(repo *mock_auth.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().UpdateStatus(gomock.Any(), usr, user.Deleted).Return(nil),
+					repo.EXPECT().Delete(gomock.Any(), usr).Return(errDummy),
+				)
+			},
+			wantErr: errDummy,
+		},
+		{
+			name: "user is deleted",
+			prepare: func(repo *mock_auth.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().UpdateStatus(gomock.Any(), usr, user.Deleted).Return(nil),
+					repo.EXPECT().Delete(gomock.Any(), usr).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			repo := mock_auth.NewMockRepository(mockCtrl)
+			tt.prepare(repo)
+
+			man := NewManager(repo)
+			err := man.DeleteUser(context.Background(), usr)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	_ "github.com/golang/mock/mockgen/model"
+)
+
+//go:generate mockgen -destination=./mocks/mock_hasher.go . PasswordHasher
+
+var (
+	ErrHashFormatIsInvalid    = errors.New("password hash has invalid format")
+	ErrHashAlgorithmUnknown   = errors.New("password hash algorithm is unknown")
+	ErrHashingIsNotSupported  = errors.New("password hasher can only verify passwords")
+	ErrHashParamsAreNotSecure = errors.New("password hasher params are not secure enough")
+)
+
+// PasswordHasher хеширует пароли со случайной солью.
+// Алгоритм, его параметры и соль хранятся вместе с хешем в закодированной строке вида $alg$params$salt$hash,
+// поэтому один и тот же пароль каждый раз дает новый хеш.
+type PasswordHasher interface {
+	// Hash возвращает закодированный хеш пароля
+	Hash(pword string) (encoded string, err error)
+	// Verify проверяет пароль по закодированному хешу
+	Verify(pword, encoded string) (ok bool, err error)
+	// NeedsRehash сообщает, что хеш сделан другим алгоритмом или с устаревшими параметрами
+	NeedsRehash(encoded string) bool
+	// CanVerify сообщает, что хеш сделан этим алгоритмом
+	CanVerify(encoded string) bool
+}
+
+var _ PasswordHasher = (*UpgradeableHasher)(nil)
+
+// UpgradeableHasher хеширует новые пароли предпочтительным алгоритмом,
+// но умеет проверять пароли, захешированные любым из известных ему алгоритмов.
+// Хеши, сделанные не предпочтительным алгоритмом, требуют перехеширования.
+type UpgradeableHasher struct {
+	preferred PasswordHasher
+	known     []PasswordHasher
+}
+
+func NewUpgradeableHasher(preferred PasswordHasher, legacy ...PasswordHasher) *UpgradeableHasher {
+	if preferred == nil {
+		panic("missing PasswordHasher, parameter must not be nil")
+	}
+	return &UpgradeableHasher{
+		preferred: preferred,
+		known:     append([]PasswordHasher{preferred}, legacy...),
+	}
+}
+
+// NewDefaultHasher argon2id для новых паролей, bcrypt и scrypt для совместимости,
+// а также проверка паролей, захешированных SHA-256 с фиксированной солью в прежних версиях сервиса
+func NewDefaultHasher() *UpgradeableHasher {
+	return NewUpgradeableHasher(
+		NewArgon2idHasher(DefaultArgon2idParams),
+		NewBcryptHasher(DefaultBcryptCost),
+		NewScryptHasher(DefaultScryptParams),
+		NewLegacyHasher(),
+	)
+}
+
+func (h *UpgradeableHasher) Hash(pword string) (encoded string, err error) {
+	return h.preferred.Hash(pword)
+}
+
+func (h *UpgradeableHasher) Verify(pword, encoded string) (ok bool, err error) {
+	for _, hasher := range h.known {
+		if hasher.CanVerify(encoded) {
+			return hasher.Verify(pword, encoded)
+		}
+	}
+	return false, ErrHashAlgorithmUnknown
+}
+
+func (h *UpgradeableHasher) NeedsRehash(encoded string) bool {
+	if !h.preferred.CanVerify(encoded) {
+		return true
+	}
+	return h.preferred.NeedsRehash(encoded)
+}
+
+func (h *UpgradeableHasher) CanVerify(encoded string) bool {
+	for _, hasher := range h.known {
+		if hasher.CanVerify(encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+// b64 кодировка соли и хеша в формате PHC
+var b64 = base64.RawStdEncoding
+
+func newSalt(n uint32) ([]byte, error) {
+	salt := make([]byte, n)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// splitPHC разбирает строку формата $alg$params$salt$hash
+func splitPHC(encoded, alg string) (params string, salt, hash []byte, err error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[0] != "" || parts[1] != alg {
+		return "", nil, nil, ErrHashFormatIsInvalid
+	}
+	salt, err = b64.DecodeString(parts[3])
+	if err != nil {
+		return "", nil, nil, ErrHashFormatIsInvalid
+	}
+	hash, err = b64.DecodeString(parts[4])
+	if err != nil {
+		return "", nil, nil, ErrHashFormatIsInvalid
+	}
+	return parts[2], salt, hash, nil
+}
+
+func equalHashes(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
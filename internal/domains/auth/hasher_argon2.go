@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2idAlg = "argon2id"
+
+// Argon2idParams параметры argon2id, Memory в KiB
+type Argon2idParams struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+	SaltLen uint32
+}
+
+// DefaultArgon2idParams рекомендованные RFC 9106 параметры для систем с ограниченной памятью
+var DefaultArgon2idParams = Argon2idParams{
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 2,
+	KeyLen:  32,
+	SaltLen: 16,
+}
+
+var _ PasswordHasher = (*Argon2idHasher)(nil)
+
+// Argon2idHasher хеш в формате $argon2id$v=19$m=65536,t=3,p=2$salt$hash
+type Argon2idHasher struct {
+	params Argon2idParams
+}
+
+func NewArgon2idHasher(params Argon2idParams) *Argon2idHasher {
+	if params.Time == 0 || params.Memory == 0 || params.Threads == 0 || params.KeyLen == 0 || params.SaltLen == 0 {
+		panic(ErrHashParamsAreNotSecure)
+	}
+	return &Argon2idHasher{params: params}
+}
+
+func (h *Argon2idHasher) Hash(pword string) (encoded string, err error) {
+	salt, err := newSalt(h.params.SaltLen)
+	if err != nil {
+		return "", err
+	}
+	p := h.params
+	hash := argon2.IDKey([]byte(pword), salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idAlg, argon2.Version, p.Memory, p.Time, p.Threads, b64.EncodeToString(salt), b64.EncodeToString(hash)), nil
+}
+
+func (h *Argon2idHasher) Verify(pword, encoded string) (ok bool, err error) {
+	p, salt, hash, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	other := argon2.IDKey([]byte(pword), salt, p.Time, p.Memory, p.Threads, uint32(len(hash)))
+	return equalHashes(hash, other), nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	p, salt, hash, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return p.Time != h.params.Time || p.Memory != h.params.Memory || p.Threads != h.params.Threads ||
+		uint32(len(hash)) != h.params.KeyLen || uint32(len(salt)) != h.params.SaltLen
+}
+
+func (h *Argon2idHasher) CanVerify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$"+argon2idAlg+"$")
+}
+
+// decodeArgon2id разбирает $argon2id$v=19$m=65536,t=3,p=2$salt$hash
+func decodeArgon2id(encoded string) (p Argon2idParams, salt, hash []byte, err error) {
+	// версия идет отдельной секцией, поэтому склеиваем ее с параметрами
+	encoded = strings.Replace(encoded, "$m=", ",m=", 1)
+	params, salt, hash, err := splitPHC(encoded, argon2idAlg)
+	if err != nil {
+		return Argon2idParams{}, nil, nil, err
+	}
+	var version int
+	_, err = fmt.Sscanf(params, "v=%d,m=%d,t=%d,p=%d", &version, &p.Memory, &p.Time, &p.Threads)
+	if err != nil || version != argon2.Version {
+		return Argon2idParams{}, nil, nil, ErrHashFormatIsInvalid
+	}
+	return p, salt, hash, nil
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const DefaultBcryptCost = 12
+
+var _ PasswordHasher = (*BcryptHasher)(nil)
+
+// BcryptHasher хеш в собственном формате bcrypt $2a$12$<salt><hash>
+// Пароли длиннее 72 байт bcrypt обрезает.
+type BcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		panic(ErrHashParamsAreNotSecure)
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+func (h *BcryptHasher) Hash(pword string) (encoded string, err error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(pword), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Verify(pword, encoded string) (ok bool, err error) {
+	err = bcrypt.CompareHashAndPassword([]byte(encoded), []byte(pword))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+	return cost != h.cost
+}
+
+func (h *BcryptHasher) CanVerify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"strings"
+)
+
+const legacySalt = "gPhRmRt"
+
+var _ PasswordHasher = (*LegacyHasher)(nil)
+
+// LegacyHasher проверяет пароли, захешированные прежними версиями сервиса: SHA-256 с фиксированной солью в base64.
+// Новые хеши этим алгоритмом не создаются, любой такой хеш требует перехеширования.
+type LegacyHasher struct {
+	hasher *SaltedHash
+}
+
+func NewLegacyHasher() *LegacyHasher {
+	return &LegacyHasher{hasher: NewSaltedHashWithDefaultMixer(sha256.New(), []byte(legacySalt))}
+}
+
+func (h *LegacyHasher) Hash(string) (encoded string, err error) {
+	return "", ErrHashingIsNotSupported
+}
+
+func (h *LegacyHasher) Verify(pword, encoded string) (ok bool, err error) {
+	return equalHashes([]byte(encoded), []byte(base64.URLEncoding.EncodeToString(h.hasher.Sum([]byte(pword))))), nil
+}
+
+func (h *LegacyHasher) NeedsRehash(string) bool {
+	return true
+}
+
+func (h *LegacyHasher) CanVerify(encoded string) bool {
+	return encoded != "" && !strings.HasPrefix(encoded, "$")
+}
+
+type SaltedHash struct {
+	hash.Hash
+	salt  []byte
+	mixer func([]byte, []byte) []byte
+}
+
+func NewSaltedHash(h hash.Hash, salt []byte, mixer func([]byte, []byte) []byte) *SaltedHash {
+	return &SaltedHash{Hash: h, salt: salt, mixer: mixer}
+}
+
+func NewSaltedHashWithDefaultMixer(h hash.Hash, salt []byte) *SaltedHash {
+	m := func(body []byte, salt []byte) []byte {
+		return append(body, salt...)
+	}
+	return NewSaltedHash(h, salt, m)
+}
+
+func (h SaltedHash) Sum(b []byte) []byte {
+	return h.Hash.Sum(h.mixer(b, h.salt))
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const scryptAlg = "scrypt"
+
+// ScryptParams параметры scrypt, N = 2^LogN
+type ScryptParams struct {
+	LogN    uint8
+	R       int
+	P       int
+	KeyLen  int
+	SaltLen uint32
+}
+
+var DefaultScryptParams = ScryptParams{
+	LogN:    15,
+	R:       8,
+	P:       1,
+	KeyLen:  32,
+	SaltLen: 16,
+}
+
+var _ PasswordHasher = (*ScryptHasher)(nil)
+
+// ScryptHasher хеш в формате $scrypt$ln=15,r=8,p=1$salt$hash
+type ScryptHasher struct {
+	params ScryptParams
+}
+
+func NewScryptHasher(params ScryptParams) *ScryptHasher {
+	if params.LogN == 0 || params.R == 0 || params.P == 0 || params.KeyLen == 0 || params.SaltLen == 0 {
+		panic(ErrHashParamsAreNotSecure)
+	}
+	return &ScryptHasher{params: params}
+}
+
+func (h *ScryptHasher) Hash(pword string) (encoded string, err error) {
+	salt, err := newSalt(h.params.SaltLen)
+	if err != nil {
+		return "", err
+	}
+	p := h.params
+	hash, err := scrypt.Key([]byte(pword), salt, 1<<p.LogN, p.R, p.P, p.KeyLen)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$%s$ln=%d,r=%d,p=%d$%s$%s",
+		scryptAlg, p.LogN, p.R, p.P, b64.EncodeToString(salt), b64.EncodeToString(hash)), nil
+}
+
+func (h *ScryptHasher) Verify(pword, encoded string) (ok bool, err error) {
+	p, salt, hash, err := decodeScrypt(encoded)
+	if err != nil {
+		return false, err
+	}
+	other, err := scrypt.Key([]byte(pword), salt, 1<<p.LogN, p.R, p.P, len(hash))
+	if err != nil {
+		return false, err
+	}
+	return equalHashes(hash, other), nil
+}
+
+func (h *ScryptHasher) NeedsRehash(encoded string) bool {
+	p, salt, hash, err := decodeScrypt(encoded)
+	if err != nil {
+		return true
+	}
+	return p.LogN != h.params.LogN || p.R != h.params.R || p.P != h.params.P ||
+		len(hash) != h.params.KeyLen || uint32(len(salt)) != h.params.SaltLen
+}
+
+func (h *ScryptHasher) CanVerify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$"+scryptAlg+"$")
+}
+
+func decodeScrypt(encoded string) (p ScryptParams, salt, hash []byte, err error) {
+	params, salt, hash, err := splitPHC(encoded, scryptAlg)
+	if err != nil {
+		return ScryptParams{}, nil, nil, err
+	}
+	_, err = fmt.Sscanf(params, "ln=%d,r=%d,p=%d", &p.LogN, &p.R, &p.P)
+	if err != nil || p.LogN == 0 || p.LogN > 30 {
+		return ScryptParams{}, nil, nil, ErrHashFormatIsInvalid
+	}
+	return p, salt, hash, nil
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// параметры, достаточные для проверки логики, но не замедляющие тесты
+var (
+	testArgon2idParams = Argon2idParams{Time: 1, Memory: 1024, Threads: 1, KeyLen: 16, SaltLen: 8}
+	testScryptParams   = ScryptParams{LogN: 4, R: 8, P: 1, KeyLen: 16, SaltLen: 8}
+	testBcryptCost     = 4
+)
+
+func TestPasswordHashers(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		prefix string
+	}{
+		{name: "argon2id", hasher: NewArgon2idHasher(testArgon2idParams), prefix: "$argon2id$v=19$m=1024,t=1,p=1$"},
+		{name: "bcrypt", hasher: NewBcryptHasher(testBcryptCost), prefix: "$2a$04$"},
+		{name: "scrypt", hasher: NewScryptHasher(testScryptParams), prefix: "$scrypt$ln=4,r=8,p=1$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.hasher.Hash("pa$$word")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(encoded, tt.prefix), encoded)
+			assert.True(t, tt.hasher.CanVerify(encoded))
+			assert.False(t, tt.hasher.NeedsRehash(encoded))
+
+			again, err := tt.hasher.Hash("pa$$word")
+			require.NoError(t, err)
+			assert.NotEqual(t, encoded, again, "same password must give different hashes")
+
+			ok, err := tt.hasher.Verify("pa$$word", encoded)
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = tt.hasher.Verify("password", encoded)
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestPasswordHashers_NeedsRehash(t *testing.T) {
+	old := NewArgon2idHasher(testArgon2idParams)
+	encoded, err := old.Hash("test")
+	require.NoError(t, err)
+
+	stronger := testArgon2idParams
+	stronger.Time = 2
+	assert.True(t, NewArgon2idHasher(stronger).NeedsRehash(encoded))
+
+	bc, err := NewBcryptHasher(testBcryptCost).Hash("test")
+	require.NoError(t, err)
+	assert.True(t, NewBcryptHasher(testBcryptCost+1).NeedsRehash(bc))
+
+	sc, err := NewScryptHasher(testScryptParams).Hash("test")
+	require.NoError(t, err)
+	strongerScrypt := testScryptParams
+	strongerScrypt.LogN++
+	assert.True(t, NewScryptHasher(strongerScrypt).NeedsRehash(sc))
+}
+
+func TestUpgradeableHasher(t *testing.T) {
+	bcrypter := NewBcryptHasher(testBcryptCost)
+	h := NewUpgradeableHasher(NewArgon2idHasher(testArgon2idParams), bcrypter, NewLegacyHasher())
+
+	legacy := base64.URLEncoding.EncodeToString(sha256.New().Sum([]byte("test" + legacySalt)))
+	bc, err := bcrypter.Hash("test")
+	require.NoError(t, err)
+	current, err := h.Hash("test")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		encoded     string
+		wantOK      bool
+		wantErr     bool
+		needsRehash bool
+	}{
+		{name: "preferred algorithm", encoded: current, wantOK: true, needsRehash: false},
+		{name: "legacy algorithm", encoded: bc, wantOK: true, needsRehash: true},
+		{name: "fixed salt sha-256", encoded: legacy, wantOK: true, needsRehash: true},
+		{name: "unknown algorithm", encoded: "$md5$abc$def", wantErr: true, needsRehash: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := h.Verify("test", tt.encoded)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.needsRehash, h.NeedsRehash(tt.encoded))
+		})
+	}
+}
+
+func TestLegacyHasher_Hash(t *testing.T) {
+	_, err := NewLegacyHasher().Hash("test")
+	assert.ErrorIs(t, err, ErrHashingIsNotSupported)
+}
@@ -49,6 +49,20 @@ func (mr *MockRepositoryMockRecorder) Create(arg0, arg1, arg2, arg3 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0, arg1, arg2, arg3)
 }
 
+// Delete mocks base method.
+func (m *MockRepository) Delete(arg0 context.Context, arg1 user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0, arg1)
+}
+
 // Read mocks base method.
 func (m *MockRepository) Read(arg0 context.Context, arg1 string) (user.User, error) {
 	m.ctrl.T.Helper()
@@ -64,17 +78,91 @@ func (mr *MockRepositoryMockRecorder) Read(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockRepository)(nil).Read), arg0, arg1)
 }
 
-// ReadWithPassword mocks base method.
-func (m *MockRepository) ReadWithPassword(arg0 context.Context, arg1, arg2 string) (user.User, error) {
+// ReadLogin mocks base method.
+func (m *MockRepository) ReadLogin(arg0 context.Context, arg1 user.User) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReadWithPassword", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ReadLogin", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadLogin indicates an expected call of ReadLogin.
+func (mr *MockRepositoryMockRecorder) ReadLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadLogin", reflect.TypeOf((*MockRepository)(nil).ReadLogin), arg0, arg1)
+}
+
+// ReadPasswordHash mocks base method.
+func (m *MockRepository) ReadPasswordHash(arg0 context.Context, arg1 string) (user.User, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadPasswordHash", arg0, arg1)
 	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReadPasswordHash indicates an expected call of ReadPasswordHash.
+func (mr *MockRepositoryMockRecorder) ReadPasswordHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPasswordHash", reflect.TypeOf((*MockRepository)(nil).ReadPasswordHash), arg0, arg1)
+}
+
+// ReadStatus mocks base method.
+func (m *MockRepository) ReadStatus(arg0 context.Context, arg1 user.User) (user.Status, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadStatus", arg0, arg1)
+	ret0, _ := ret[0].(user.Status)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadStatus indicates an expected call of ReadStatus.
+func (mr *MockRepositoryMockRecorder) ReadStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadStatus", reflect.TypeOf((*MockRepository)(nil).ReadStatus), arg0, arg1)
+}
+
+// ReadUserPasswordHash mocks base method.
+func (m *MockRepository) ReadUserPasswordHash(arg0 context.Context, arg1 user.User) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadUserPasswordHash", arg0, arg1)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ReadWithPassword indicates an expected call of ReadWithPassword.
-func (mr *MockRepositoryMockRecorder) ReadWithPassword(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ReadUserPasswordHash indicates an expected call of ReadUserPasswordHash.
+func (mr *MockRepositoryMockRecorder) ReadUserPasswordHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadUserPasswordHash", reflect.TypeOf((*MockRepository)(nil).ReadUserPasswordHash), arg0, arg1)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockRepository) UpdatePasswordHash(arg0 context.Context, arg1 user.User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockRepositoryMockRecorder) UpdatePasswordHash(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockRepository)(nil).UpdatePasswordHash), arg0, arg1, arg2)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockRepository) UpdateStatus(arg0 context.Context, arg1 user.User, arg2 user.Status) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockRepositoryMockRecorder) UpdateStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadWithPassword", reflect.TypeOf((*MockRepository)(nil).ReadWithPassword), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockRepository)(nil).UpdateStatus), arg0, arg1, arg2)
 }
@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth (interfaces: ResetTokenRepository,Notifier)
+
+// Package mock_auth is a generated GoMock package.
+package mock_auth
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	user "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockResetTokenRepository is a mock of ResetTokenRepository interface.
+type MockResetTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockResetTokenRepositoryMockRecorder
+}
+
+// MockResetTokenRepositoryMockRecorder is the mock recorder for MockResetTokenRepository.
+type MockResetTokenRepositoryMockRecorder struct {
+	mock *MockResetTokenRepository
+}
+
+// NewMockResetTokenRepository creates a new mock instance.
+func NewMockResetTokenRepository(ctrl *gomock.Controller) *MockResetTokenRepository {
+	mock := &MockResetTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockResetTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResetTokenRepository) EXPECT() *MockResetTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateResetToken mocks base method.
+func (m *MockResetTokenRepository) CreateResetToken(arg0 context.Context, arg1 user.User, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResetToken", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateResetToken indicates an expected call of CreateResetToken.
+func (mr *MockResetTokenRepositoryMockRecorder) CreateResetToken(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResetToken", reflect.TypeOf((*MockResetTokenRepository)(nil).CreateResetToken), arg0, arg1, arg2, arg3)
+}
+
+// UseResetToken mocks base method.
+func (m *MockResetTokenRepository) UseResetToken(arg0 context.Context, arg1 string) (user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseResetToken", arg0, arg1)
+	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseResetToken indicates an expected call of UseResetToken.
+func (mr *MockResetTokenRepositoryMockRecorder) UseResetToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseResetToken", reflect.TypeOf((*MockResetTokenRepository)(nil).UseResetToken), arg0, arg1)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// NotifyPasswordReset mocks base method.
+func (m *MockNotifier) NotifyPasswordReset(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyPasswordReset", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyPasswordReset indicates an expected call of NotifyPasswordReset.
+func (mr *MockNotifierMockRecorder) NotifyPasswordReset(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyPasswordReset", reflect.TypeOf((*MockNotifier)(nil).NotifyPasswordReset), arg0, arg1, arg2, arg3)
+}
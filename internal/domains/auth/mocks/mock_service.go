@@ -64,6 +64,76 @@ func (mr *MockCredentialManagerMockRecorder) AuthenticateUser(arg0, arg1, arg2 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateUser", reflect.TypeOf((*MockCredentialManager)(nil).AuthenticateUser), arg0, arg1, arg2)
 }
 
+// ChangePassword mocks base method.
+func (m *MockCredentialManager) ChangePassword(arg0 context.Context, arg1 user.User, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockCredentialManagerMockRecorder) ChangePassword(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockCredentialManager)(nil).ChangePassword), arg0, arg1, arg2, arg3)
+}
+
+// CheckActive mocks base method.
+func (m *MockCredentialManager) CheckActive(arg0 context.Context, arg1 user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckActive", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckActive indicates an expected call of CheckActive.
+func (mr *MockCredentialManagerMockRecorder) CheckActive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckActive", reflect.TypeOf((*MockCredentialManager)(nil).CheckActive), arg0, arg1)
+}
+
+// DeleteUser mocks base method.
+func (m *MockCredentialManager) DeleteUser(arg0 context.Context, arg1 user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockCredentialManagerMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockCredentialManager)(nil).DeleteUser), arg0, arg1)
+}
+
+// DisableUser mocks base method.
+func (m *MockCredentialManager) DisableUser(arg0 context.Context, arg1 user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockCredentialManagerMockRecorder) DisableUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockCredentialManager)(nil).DisableUser), arg0, arg1)
+}
+
+// EnableUser mocks base method.
+func (m *MockCredentialManager) EnableUser(arg0 context.Context, arg1 user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableUser indicates an expected call of EnableUser.
+func (mr *MockCredentialManagerMockRecorder) EnableUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUser", reflect.TypeOf((*MockCredentialManager)(nil).EnableUser), arg0, arg1)
+}
+
 // GetUser mocks base method.
 func (m *MockCredentialManager) GetUser(arg0 context.Context, arg1 string) (user.User, error) {
 	m.ctrl.T.Helper()
@@ -78,3 +148,45 @@ func (mr *MockCredentialManagerMockRecorder) GetUser(arg0, arg1 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockCredentialManager)(nil).GetUser), arg0, arg1)
 }
+
+// NormalizeLogin mocks base method.
+func (m *MockCredentialManager) NormalizeLogin(arg0 string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NormalizeLogin", arg0)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// NormalizeLogin indicates an expected call of NormalizeLogin.
+func (mr *MockCredentialManagerMockRecorder) NormalizeLogin(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NormalizeLogin", reflect.TypeOf((*MockCredentialManager)(nil).NormalizeLogin), arg0)
+}
+
+// SetPassword mocks base method.
+func (m *MockCredentialManager) SetPassword(arg0 context.Context, arg1 user.User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPassword indicates an expected call of SetPassword.
+func (mr *MockCredentialManagerMockRecorder) SetPassword(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassword", reflect.TypeOf((*MockCredentialManager)(nil).SetPassword), arg0, arg1, arg2)
+}
+
+// VerifyPassword mocks base method.
+func (m *MockCredentialManager) VerifyPassword(arg0 context.Context, arg1 user.User, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyPassword indicates an expected call of VerifyPassword.
+func (mr *MockCredentialManagerMockRecorder) VerifyPassword(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPassword", reflect.TypeOf((*MockCredentialManager)(nil).VerifyPassword), arg0, arg1, arg2)
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/uow"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/rs/zerolog/log"
+)
+
+//go:generate mockgen -destination=./mocks/mock_password.go . ResetTokenRepository,Notifier
+
+const (
+	resetTokenTTL = 30 * time.Minute
+	resetTokenLen = 32
+)
+
+type ResetTokenRepository interface {
+	// CreateResetToken сохраняет хеш токена сброса пароля. Ранее выданные пользователю токены становятся недействительными.
+	CreateResetToken(ctx context.Context, usr user.User, hash string, expiresAt time.Time) error
+	// UseResetToken погашает токен и возвращает его пользователя.
+	// Если токена нет, он истек или уже использован - возвращает errors.ErrResetTokenIsInvalid
+	UseResetToken(ctx context.Context, hash string) (usr user.User, err error)
+}
+
+// Notifier доставляет пользователю токен сброса пароля
+type Notifier interface {
+	NotifyPasswordReset(ctx context.Context, login, token string, expiresAt time.Time) error
+}
+
+var _ app.PasswordManager = (*PasswordService)(nil)
+
+type PasswordService struct {
+	credMan  CredentialManager
+	resets   ResetTokenRepository
+	notifier Notifier
+	uow      uow.UnitOfWork
+}
+
+func NewPasswordService(credMan CredentialManager, resets ResetTokenRepository, notifier Notifier,
+	uow uow.UnitOfWork) *PasswordService {
+	if credMan == nil {
+		panic("missing CredentialManager, parameter must not be nil")
+	}
+	if resets == nil {
+		panic("missing ResetTokenRepository, parameter must not be nil")
+	}
+	if notifier == nil {
+		panic("missing Notifier, parameter must not be nil")
+	}
+	if uow == nil {
+		panic("missing uow.UnitOfWork, parameter must not be nil")
+	}
+	return &PasswordService{credMan: credMan, resets: resets, notifier: notifier, uow: uow}
+}
+
+func (s PasswordService) ChangePassword(ctx context.Context, usr user.User, oldPword, newPword string) error {
+	if newPword == "" {
+		return errors2.ErrPasswordIsEmpty
+	}
+	return s.credMan.ChangePassword(ctx, usr, oldPword, newPword)
+}
+
+// RequestPasswordReset для неизвестного логина ничего не делает, чтобы по ответу нельзя было перебирать логины
+func (s PasswordService) RequestPasswordReset(ctx context.Context, login string) error {
+	usr, err := s.credMan.GetUser(ctx, login)
+	if err != nil {
+		log.Debug().Err(err).Msg("password reset is requested for unknown login")
+		return nil
+	}
+
+	token, err := newResetToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(resetTokenTTL)
+	err = s.resets.CreateResetToken(ctx, usr, hashResetToken(token), expiresAt)
+	if err != nil {
+		return err
+	}
+	return s.notifier.NotifyPasswordReset(ctx, login, token, expiresAt)
+}
+
+// ResetPassword погашает токен и меняет пароль в одной транзакции: если пароль сменить не удалось, токен остается действующим
+func (s PasswordService) ResetPassword(ctx context.Context, token, newPword string) (usr user.User, err error) {
+	if newPword == "" {
+		return user.User{}, errors2.ErrPasswordIsEmpty
+	}
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		usr, err = s.resets.UseResetToken(ctx, hashResetToken(token))
+		if err != nil {
+			return err
+		}
+		return s.credMan.SetPassword(ctx, usr, newPword)
+	})
+	if err != nil {
+		return user.User{}, err
+	}
+	return usr, nil
+}
+
+func newResetToken() (string, error) {
+	b := make([]byte, resetTokenLen)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashResetToken в хранилище попадает только хеш токена
+func hashResetToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
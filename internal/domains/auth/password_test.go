@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mock_auth "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth/mocks"
+	mock_uow "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/uow/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type passwordFields struct {
+	credMan  *mock_auth.MockCredentialManager
+	resets   *mock_auth.MockResetTokenRepository
+	notifier *mock_auth.MockNotifier
+	uow      *mock_uow.MockUnitOfWork
+}
+
+func newPasswordFields(ctrl *gomock.Controller) passwordFields {
+	f := passwordFields{
+		credMan:  mock_auth.NewMockCredentialManager(ctrl),
+		resets:   mock_auth.NewMockResetTokenRepository(ctrl),
+		notifier: mock_auth.NewMockNotifier(ctrl),
+		uow:      mock_uow.NewMockUnitOfWork(ctrl),
+	}
+	f.uow.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	return f
+}
+
+func TestPasswordService_RequestPasswordReset(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		prepare func(f passwordFields)
+		wantErr error
+	}{
+		{
+			name: "unknown login is not revealed",
+			prepare: func(f passwordFields) {
+				f.credMan.EXPECT().GetUser(gomock.Any(), "test").Return(user.User{}, errDummy)
+			},
+		},
+		{
+			name: "can't store token",
+			prepare: func(f passwordFields) {
+				gomock.InOrder(
+					f.credMan.EXPECT().GetUser(gomock.Any(), "test").Return(usr, nil),
+					f.resets.EXPECT().CreateResetToken(gomock.Any(), usr, gomock.Any(), gomock.Any()).Return(errDummy),
+				)
+			},
+			wantErr: errDummy,
+		},
+		{
+			name: "hashed token is stored and plain token is sent",
+			prepare: func(f passwordFields) {
+				var stored string
+				gomock.InOrder(
+					f.credMan.EXPECT().GetUser(gomock.Any(), "test").Return(usr, nil),
+					f.resets.EXPECT().CreateResetToken(gomock.Any(), usr, gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, _ user.User, hash string, expiresAt time.Time) error {
+							stored = hash
+							assert.True(t, expiresAt.After(time.Now()))
+							return nil
+						}),
+					f.notifier.EXPECT().NotifyPasswordReset(gomock.Any(), "test", gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, _, token string, _ time.Time) error {
+							assert.NotEqual(t, stored, token)
+							assert.Equal(t, stored, hashResetToken(token))
+							return nil
+						}),
+				)
+			},
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			f := newPasswordFields(mockCtrl)
+			tt.prepare(f)
+
+			s := NewPasswordService(f.credMan, f.resets, f.notifier, f.uow)
+			err := s.RequestPasswordReset(context.Background(), "test")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPasswordService_ResetPassword(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		pword   string
+		prepare func(f passwordFields)
+		wantErr error
+	}{
+		{
+			name:    "empty password",
+			pword:   "",
+			prepare: func(f passwordFields) {},
+			wantErr: errors2.ErrPasswordIsEmpty,
+		},
+		{
+			name:  "invalid token",
+			pword: "new",
+			prepare: func(f passwordFields) {
+				f.resets.EXPECT().UseResetToken(gomock.Any(), hashResetToken("token")).Return(user.User{}, errors2.ErrResetTokenIsInvalid)
+			},
+			wantErr: errors2.ErrResetTokenIsInvalid,
+		},
+		{
+			name:  "can't set password",
+			pword: "new",
+			prepare: func(f passwordFields) {
+				gomock.InOrder(
+					f.resets.EXPECT().UseResetToken(gomock.Any(), hashResetToken("token")).Return(usr, nil),
+					f.credMan.EXPECT().SetPassword(gomock.Any(), usr, "new").Return(errDummy),
+				)
+			},
+			wantErr: errDummy,
+		},
+		{
+			name:  "password is reset",
+			pword: "new",
+			prepare: func(f passwordFields) {
+				gomock.InOrder(
+					f.resets.EXPECT().UseResetToken(gomock.Any(), hashResetToken("token")).Return(usr, nil),
+					f.credMan.EXPECT().SetPassword(gomock.Any(), usr, "new").Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			f := newPasswordFields(mockCtrl)
+			tt.prepare(f)
+
+			s := NewPasswordService(f.credMan, f.resets, f.notifier, f.uow)
+			got, err := s.ResetPassword(context.Background(), "token", tt.pword)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), "ResetPassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, usr, got)
+		})
+	}
+}
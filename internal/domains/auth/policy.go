@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	FieldLogin    = "login"
+	FieldPassword = "password"
+
+	RuleLength      = "length"
+	RuleCharset     = "charset"
+	RuleStrength    = "strength"
+	RuleDenylist    = "denylist"
+	RuleSameAsLogin = "same_as_login"
+
+	DefaultLoginMinLen        = 3
+	DefaultLoginMaxLen        = 64
+	DefaultLoginCharset       = `^[\p{L}\p{N}._@+-]+$`
+	DefaultPasswordMinLen     = 8
+	DefaultPasswordMaxLen     = 128
+	DefaultPasswordMinClasses = 2
+)
+
+// PolicyRules ограничения на логин и пароль
+type PolicyRules struct {
+	LoginMinLen int
+	LoginMaxLen int
+	// LoginCharset регулярное выражение, которому должен соответствовать логин после нормализации
+	LoginCharset   string
+	PasswordMinLen int
+	PasswordMaxLen int
+	// PasswordMinClasses сколько классов символов (строчные, заглавные, цифры, прочие) должно быть в пароле
+	PasswordMinClasses int
+}
+
+func DefaultPolicyRules() PolicyRules {
+	return PolicyRules{
+		LoginMinLen:        DefaultLoginMinLen,
+		LoginMaxLen:        DefaultLoginMaxLen,
+		LoginCharset:       DefaultLoginCharset,
+		PasswordMinLen:     DefaultPasswordMinLen,
+		PasswordMaxLen:     DefaultPasswordMaxLen,
+		PasswordMinClasses: DefaultPasswordMinClasses,
+	}
+}
+
+// CredentialPolicy нормализует логины и проверяет креды при регистрации и смене пароля.
+// Длины считаются в символах, а не в байтах.
+type CredentialPolicy struct {
+	rules    PolicyRules
+	charset  *regexp.Regexp
+	denylist map[string]struct{}
+}
+
+// NewCredentialPolicy denylist - пароли из утечек, сравнение без учета регистра
+func NewCredentialPolicy(rules PolicyRules, denylist []string) (*CredentialPolicy, error) {
+	charset, err := regexp.Compile(rules.LoginCharset)
+	if err != nil {
+		return nil, err
+	}
+	p := &CredentialPolicy{
+		rules:    rules,
+		charset:  charset,
+		denylist: make(map[string]struct{}, len(denylist)),
+	}
+	for _, pword := range denylist {
+		p.denylist[strings.ToLower(pword)] = struct{}{}
+	}
+	return p, nil
+}
+
+func NewDefaultCredentialPolicy() *CredentialPolicy {
+	p, err := NewCredentialPolicy(DefaultPolicyRules(), nil)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// ReadDenylist читает по паролю на строку, пустые строки и строки с # пропускаются
+func ReadDenylist(r io.Reader) (denylist []string, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denylist = append(denylist, line)
+	}
+	return denylist, scanner.Err()
+}
+
+// NormalizeLogin приводит логин к NFKC и складывает регистр, чтобы Bob и bob были одним логином.
+// cases.Caser хранит состояние, поэтому на каждый вызов создается новый.
+// Уникальность нормализованного логина в БД обеспечивает индекс на normalize_login из миграции 14_normalize_login:
+// если правило меняется, функцию и логины нужно пересчитать новой миграцией.
+func (p *CredentialPolicy) NormalizeLogin(login string) string {
+	return cases.Fold().String(norm.NFKC.String(login))
+}
+
+// ValidateLogin проверяет уже нормализованный логин
+func (p *CredentialPolicy) ValidateLogin(login string) error {
+	n := len([]rune(login))
+	if n < p.rules.LoginMinLen || n > p.rules.LoginMaxLen {
+		return violation(FieldLogin, RuleLength,
+			fmt.Sprintf("login must be from %d to %d characters long", p.rules.LoginMinLen, p.rules.LoginMaxLen))
+	}
+	if !p.charset.MatchString(login) {
+		return violation(FieldLogin, RuleCharset, "login contains not allowed characters")
+	}
+	return nil
+}
+
+// ValidatePassword login - нормализованный логин, если он известен
+func (p *CredentialPolicy) ValidatePassword(pword, login string) error {
+	n := len([]rune(pword))
+	if n < p.rules.PasswordMinLen || n > p.rules.PasswordMaxLen {
+		return violation(FieldPassword, RuleLength,
+			fmt.Sprintf("password must be from %d to %d characters long", p.rules.PasswordMinLen, p.rules.PasswordMaxLen))
+	}
+	if characterClasses(pword) < p.rules.PasswordMinClasses {
+		return violation(FieldPassword, RuleStrength,
+			fmt.Sprintf("password must contain at least %d of: lowercase, uppercase, digits, other characters",
+				p.rules.PasswordMinClasses))
+	}
+	if login != "" && p.NormalizeLogin(pword) == login {
+		return violation(FieldPassword, RuleSameAsLogin, "password must not match login")
+	}
+	if _, ok := p.denylist[strings.ToLower(pword)]; ok {
+		return violation(FieldPassword, RuleDenylist, "password is found in breached passwords list")
+	}
+	return nil
+}
+
+func characterClasses(s string) int {
+	var lower, upper, digit, other int
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower + upper + digit + other
+}
+
+func violation(field, rule, msg string) error {
+	return &errors2.PolicyViolation{Field: field, Rule: rule, Message: msg}
+}
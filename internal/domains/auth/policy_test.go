@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNewPassword = "New-passw0rd"
+
+func TestCredentialPolicy_NormalizeLogin(t *testing.T) {
+	p := NewDefaultCredentialPolicy()
+	tests := []struct {
+		login string
+		want  string
+	}{
+		{login: "bob", want: "bob"},
+		{login: "Bob", want: "bob"},
+		{login: "ＢＯＢ", want: "bob"},
+		{login: "Straße", want: "strasse"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.NormalizeLogin(tt.login), tt.login)
+	}
+}
+
+func TestCredentialPolicy_ValidateLogin(t *testing.T) {
+	p := NewDefaultCredentialPolicy()
+	tests := []struct {
+		name     string
+		login    string
+		wantRule string
+	}{
+		{name: "valid", login: "bob.smith@mail"},
+		{name: "unicode letters", login: "иван"},
+		{name: "empty", login: "", wantRule: RuleLength},
+		{name: "too short", login: "bo", wantRule: RuleLength},
+		{name: "too long", login: strings.Repeat("b", DefaultLoginMaxLen+1), wantRule: RuleLength},
+		{name: "space", login: "bob smith", wantRule: RuleCharset},
+		{name: "control char", login: "bob\x00", wantRule: RuleCharset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertViolation(t, p.ValidateLogin(tt.login), FieldLogin, tt.wantRule)
+		})
+	}
+}
+
+func TestCredentialPolicy_ValidatePassword(t *testing.T) {
+	p, err := NewCredentialPolicy(DefaultPolicyRules(), []string{"Password1"})
+	require.NoError(t, err)
+	tests := []struct {
+		name     string
+		pword    string
+		login    string
+		wantRule string
+	}{
+		{name: "valid", pword: testNewPassword, login: "bob"},
+		{name: "empty", pword: "", wantRule: RuleLength},
+		{name: "too short", pword: "Ab1", wantRule: RuleLength},
+		{name: "one class", pword: "abcdefghij", wantRule: RuleStrength},
+		{name: "same as login", pword: "Bob.Smith", login: "bob.smith", wantRule: RuleSameAsLogin},
+		{name: "breached", pword: "PASSWORD1", wantRule: RuleDenylist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertViolation(t, p.ValidatePassword(tt.pword, tt.login), FieldPassword, tt.wantRule)
+		})
+	}
+}
+
+func TestReadDenylist(t *testing.T) {
+	got, err := ReadDenylist(strings.NewReader("# top passwords\n123456\n\n  qwerty  \n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"123456", "qwerty"}, got)
+}
+
+func assertViolation(t *testing.T, err error, field, rule string) {
+	t.Helper()
+	if rule == "" {
+		assert.NoError(t, err)
+		return
+	}
+	require.ErrorIs(t, err, errors2.ErrCredentialPolicyViolation)
+	var v *errors2.PolicyViolation
+	require.True(t, errors.As(err, &v))
+	assert.Equal(t, field, v.Field)
+	assert.Equal(t, rule, v.Rule)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/uow"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
 	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
 	_ "github.com/golang/mock/mockgen/model"
@@ -15,65 +16,114 @@ type CredentialManager interface {
 	AddNewUser(ctx context.Context, usr user.User, login, pword string) (err error)
 	GetUser(ctx context.Context, login string) (usr user.User, err error)
 	AuthenticateUser(ctx context.Context, login, pword string) (usr user.User, err error)
-	// DisableUser(user user.User) error
+	NormalizeLogin(login string) string
+	CheckActive(ctx context.Context, usr user.User) error
+	ChangePassword(ctx context.Context, usr user.User, oldPword, newPword string) error
+	SetPassword(ctx context.Context, usr user.User, pword string) error
+	VerifyPassword(ctx context.Context, usr user.User, pword string) error
+	DisableUser(ctx context.Context, usr user.User) error
+	EnableUser(ctx context.Context, usr user.User) error
+	DeleteUser(ctx context.Context, usr user.User) error
 }
 
-var _ app.Authenticator = (*Service)(nil)
+var (
+	_ app.Authenticator  = (*Service)(nil)
+	_ app.AccountManager = (*Service)(nil)
+)
 
 type Service struct {
 	userSvc user.Registerer
 	credMan CredentialManager
+	uow     uow.UnitOfWork
 }
 
-func NewService(userSvc user.Registerer, credMan CredentialManager) *Service {
+func NewService(userSvc user.Registerer, credMan CredentialManager, uow uow.UnitOfWork) *Service {
 	if userSvc == nil {
 		panic("missing user.Registerer, parameter must not be nil")
 	}
 	if credMan == nil {
 		panic("missing CredentialManager, parameter must not be nil")
 	}
+	if uow == nil {
+		panic("missing uow.UnitOfWork, parameter must not be nil")
+	}
 	return &Service{
 		userSvc: userSvc,
 		credMan: credMan,
+		uow:     uow,
 	}
 }
 
-func NewServiceWithDefaultCredMan(repo Repository, userSvc user.Registerer) *Service {
+func NewServiceWithDefaultCredMan(repo Repository, userSvc user.Registerer, uow uow.UnitOfWork) *Service {
 	if repo == nil {
 		panic("missing Repository, parameter must not be nil")
 	}
 	if userSvc == nil {
 		panic("missing user.Registerer, parameter must not be nil")
 	}
-	return NewService(userSvc, NewManager(repo))
+	return NewService(userSvc, NewManager(repo), uow)
 }
 
-// SignIn регистрирует нового пользователя с новым id и добавляет ему логин/пароль
+// SignIn регистрирует нового пользователя с новым id, добавляет ему логин/пароль и возвращает созданного пользователя
 // В случае если такой логин уже есть, то возвращает ошибку.
-// id пользователя можно получить только после Login с этой же парой логин/пароль
-// ToDo удалить пользователя (компенсация), если ошибка при добавлении кред, так как пользователь и его креды должны быть в БД
-// ToDo альтернативно можно проврять, что пользовтель есть, а кред нет, тогда просто добавить креды
-// В реальном проекте я бы наплевал на архитектурную красоту в сервисе и сделал бы транзакцию: добавление пользователя+креды
-func (s Service) SignIn(ctx context.Context, login, pword string) error {
-	// найти пользователя по логину - если есть, то занят
-	_, err := s.credMan.GetUser(ctx, login)
-	if err == nil {
-		return errors2.ErrLoginIsInUseAlready
-	}
-	// если не занят, то создаем пустого пользователя и регистрируем его
-	usr := user.NewUser()
-	err = s.userSvc.RegisterNewUser(ctx, usr)
-	if err != nil {
-		return err
-	}
-	// создаем креды на пользователя
-	err = s.credMan.AddNewUser(ctx, usr, login, pword)
+// Пользователь и его креды создаются в одной транзакции: если креды добавить не удалось
+// (например, параллельная регистрация с тем же логином), то пользователь тоже не сохраняется.
+func (s Service) SignIn(ctx context.Context, login, pword string) (usr user.User, err error) {
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// найти пользователя по логину - если есть, то занят
+		_, err := s.credMan.GetUser(ctx, login)
+		if err == nil {
+			return errors2.ErrLoginIsInUseAlready
+		}
+		// если не занят, то создаем пустого пользователя и регистрируем его
+		usr = user.NewUser()
+		err = s.userSvc.RegisterNewUser(ctx, usr)
+		if err != nil {
+			return err
+		}
+		// создаем креды на пользователя
+		return s.credMan.AddNewUser(ctx, usr, login, pword)
+	})
 	if err != nil {
-		return err
+		return user.User{}, err
 	}
-	return nil
+	return usr, nil
 }
 
 func (s Service) Login(ctx context.Context, login, pword string) (user user.User, err error) {
 	return s.credMan.AuthenticateUser(ctx, login, pword)
 }
+
+func (s Service) NormalizeLogin(login string) string {
+	return s.credMan.NormalizeLogin(login)
+}
+
+func (s Service) CheckActive(ctx context.Context, usr user.User) error {
+	return s.credMan.CheckActive(ctx, usr)
+}
+
+func (s Service) DisableUser(ctx context.Context, usr user.User) error {
+	return s.credMan.DisableUser(ctx, usr)
+}
+
+func (s Service) EnableUser(ctx context.Context, usr user.User) error {
+	return s.credMan.EnableUser(ctx, usr)
+}
+
+// DeleteUser смена статуса и удаление кредов выполняются в одной транзакции
+func (s Service) DeleteUser(ctx context.Context, usr user.User) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.credMan.DeleteUser(ctx, usr)
+	})
+}
+
+// DeleteAccount удаляет учетную запись по просьбе самого пользователя, если он подтвердил пароль
+func (s Service) DeleteAccount(ctx context.Context, usr user.User, pword string) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		err := s.credMan.VerifyPassword(ctx, usr, pword)
+		if err != nil {
+			return err
+		}
+		return s.credMan.DeleteUser(ctx, usr)
+	})
+}
@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	mock_auth "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth/mocks"
+	mock_uow "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/uow/mocks"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
 	mock_user "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user/mocks"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
 	"github.com/golang/mock/gomock"
 )
 
@@ -93,6 +95,11 @@ func TestService_SignIn(t *testing.T) {
 
 			reg := mock_user.NewMockRegisterer(mockCtrl)
 			man := mock_auth.NewMockCredentialManager(mockCtrl)
+			tx := mock_uow.NewMockUnitOfWork(mockCtrl)
+			tx.EXPECT().Do(context.Background(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
 
 			f := fields{
 				userSvc: reg,
@@ -102,10 +109,60 @@ func TestService_SignIn(t *testing.T) {
 				tt.prepare(&f)
 			}
 
-			s := NewService(reg, man)
-			if err := s.SignIn(context.Background(), tt.args.login, tt.args.pword); (err != nil) != tt.wantErr {
+			s := NewService(reg, man, tx)
+			usr, err := s.SignIn(context.Background(), tt.args.login, tt.args.pword)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("SignIn() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if (usr.ID == "") != tt.wantErr {
+				t.Errorf("SignIn() user = %v, wantErr %v", usr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_DeleteAccount(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		prepare func(credMan *mock_auth.MockCredentialManager)
+		wantErr error
+	}{
+		{
+			name: "wrong password",
+			prepare: func(credMan *mock_auth.MockCredentialManager) {
+				credMan.EXPECT().VerifyPassword(gomock.Any(), usr, "test").Return(errors2.ErrPasswordIsIncorrect)
+			},
+			wantErr: errors2.ErrPasswordIsIncorrect,
+		},
+		{
+			name: "account is deleted",
+			prepare: func(credMan *mock_auth.MockCredentialManager) {
+				gomock.InOrder(
+					credMan.EXPECT().VerifyPassword(gomock.Any(), usr, "test").Return(nil),
+					credMan.EXPECT().DeleteUser(gomock.Any(), usr).Return(nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			man := mock_auth.NewMockCredentialManager(mockCtrl)
+			tx := mock_uow.NewMockUnitOfWork(mockCtrl)
+			tx.EXPECT().Do(context.Background(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+			tt.prepare(man)
+
+			s := NewService(mock_user.NewMockRegisterer(mockCtrl), man, tx)
+			err := s.DeleteAccount(context.Background(), usr, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
 		})
 	}
 }
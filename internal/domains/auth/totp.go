@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	totpSecretLen = 20
+	totpDigits    = 6
+	totpPeriod    = 30 * time.Second
+	// totpSkew сколько соседних интервалов принимается, чтобы не зависеть от расхождения часов
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpKey секрет TOTP по RFC 6238: HMAC-SHA1, 6 цифр, интервал 30 секунд.
+// SHA1 - значение по умолчанию в RFC, другие алгоритмы поддерживают не все приложения-аутентификаторы.
+type totpKey []byte
+
+func newTOTPKey() (totpKey, error) {
+	b := make([]byte, totpSecretLen)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func parseTOTPKey(secret string) (totpKey, error) {
+	return totpEncoding.DecodeString(secret)
+}
+
+// String секрет в base32, в таком виде его вводят в приложение-аутентификатор
+func (k totpKey) String() string {
+	return totpEncoding.EncodeToString(k)
+}
+
+// URI ссылка otpauth://, которую приложения-аутентификаторы принимают через QR-код
+func (k totpKey) URI(issuer, account string) string {
+	v := url.Values{}
+	v.Set("secret", k.String())
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(totpDigits))
+	v.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + v.Encode()
+}
+
+// code код для интервала step по RFC 4226
+func (k totpKey) code(step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, k)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, bin%1000000)
+}
+
+// verify возвращает интервал, которому соответствует код. Интервал нужен, чтобы не принимать код повторно
+func (k totpKey) verify(code string, now time.Time) (step int64, ok bool) {
+	current := totpStep(now)
+	for i := -totpSkew; i <= totpSkew; i++ {
+		s := current + int64(i)
+		if subtle.ConstantTimeCompare([]byte(k.code(s)), []byte(code)) == 1 {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+func totpStep(t time.Time) int64 {
+	return t.Unix() / int64(totpPeriod.Seconds())
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Тестовые векторы RFC 6238 для SHA1, последние 6 цифр из 8
+func TestTOTPKey_code(t *testing.T) {
+	key := totpKey("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, key.code(totpStep(time.Unix(tt.unix, 0))))
+	}
+}
+
+func TestTOTPKey_verify(t *testing.T) {
+	key, err := newTOTPKey()
+	require.NoError(t, err)
+	now := time.Unix(1111111109, 0)
+	current := totpStep(now)
+
+	for _, shift := range []int64{-1, 0, 1} {
+		step, ok := key.verify(key.code(current+shift), now)
+		require.True(t, ok)
+		require.Equal(t, current+shift, step)
+	}
+	_, ok := key.verify(key.code(current+2), now)
+	require.False(t, ok)
+	_, ok = key.verify("", now)
+	require.False(t, ok)
+}
+
+func TestTOTPKey_String(t *testing.T) {
+	key, err := newTOTPKey()
+	require.NoError(t, err)
+	parsed, err := parseTOTPKey(key.String())
+	require.NoError(t, err)
+	require.Equal(t, key, parsed)
+
+	uri := key.URI("GopherMart", "user")
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/GopherMart:user?"))
+	require.Contains(t, uri, "secret="+key.String())
+}
@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/uow"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/pkg/errors"
+)
+
+//go:generate mockgen -destination=./mocks/mock_twofactor.go . TwoFactorRepository
+
+const (
+	recoveryCodesCount = 10
+	recoveryCodeLen    = 10
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorRepository interface {
+	// SaveTwoFactorSecret сохраняет секрет неподтвержденной настройки, заменяя прежний неподтвержденный.
+	// Если 2FA уже включена - возвращает errors.ErrTwoFactorIsEnabledAlready
+	SaveTwoFactorSecret(ctx context.Context, usr user.User, secret string) error
+	// ReadTwoFactor если пользователь не начинал настройку - возвращает errors.ErrTwoFactorIsNotEnabled
+	ReadTwoFactor(ctx context.Context, usr user.User) (secret string, enabled bool, err error)
+	// EnableTwoFactor включает 2FA, step - интервал кода, которым подтверждена настройка
+	EnableTwoFactor(ctx context.Context, usr user.User, step int64, recoveryHashes []string) error
+	// UseTwoFactorStep запоминает интервал использованного кода.
+	// Если интервал не новее последнего использованного - возвращает errors.ErrTwoFactorCodeIsInvalid
+	UseTwoFactorStep(ctx context.Context, usr user.User, step int64) error
+	// UseRecoveryCode погашает код восстановления.
+	// Если кода нет или он уже использован - возвращает errors.ErrTwoFactorCodeIsInvalid
+	UseRecoveryCode(ctx context.Context, usr user.User, hash string) error
+	// DeleteTwoFactor удаляет секрет и коды восстановления
+	DeleteTwoFactor(ctx context.Context, usr user.User) error
+}
+
+var _ app.TwoFactorManager = (*TwoFactorService)(nil)
+
+// TwoFactorService второй фактор по TOTP (RFC 6238) с одноразовыми кодами восстановления.
+// Код каждого интервала принимается только один раз, чтобы перехваченный код нельзя было повторить.
+type TwoFactorService struct {
+	repo   TwoFactorRepository
+	issuer string
+	uow    uow.UnitOfWork
+}
+
+// NewTwoFactorService issuer - название сервиса в приложении-аутентификаторе
+func NewTwoFactorService(repo TwoFactorRepository, issuer string, uow uow.UnitOfWork) *TwoFactorService {
+	if repo == nil {
+		panic("missing TwoFactorRepository, parameter must not be nil")
+	}
+	if uow == nil {
+		panic("missing uow.UnitOfWork, parameter must not be nil")
+	}
+	return &TwoFactorService{repo: repo, issuer: issuer, uow: uow}
+}
+
+// SetupTwoFactor выдает новый секрет. 2FA включается только после подтверждения кодом из приложения.
+func (s TwoFactorService) SetupTwoFactor(ctx context.Context, usr user.User) (setup app.TwoFactorSetup, err error) {
+	key, err := newTOTPKey()
+	if err != nil {
+		return app.TwoFactorSetup{}, err
+	}
+	err = s.repo.SaveTwoFactorSecret(ctx, usr, key.String())
+	if err != nil {
+		return app.TwoFactorSetup{}, err
+	}
+	return app.TwoFactorSetup{Secret: key.String(), URI: key.URI(s.issuer, usr.ID)}, nil
+}
+
+// ConfirmTwoFactor включает 2FA и возвращает коды восстановления. Коды показываются пользователю только один раз.
+func (s TwoFactorService) ConfirmTwoFactor(ctx context.Context, usr user.User, code string) (recovery []string, err error) {
+	secret, enabled, err := s.repo.ReadTwoFactor(ctx, usr)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, errors2.ErrTwoFactorIsEnabledAlready
+	}
+	step, err := verifyTOTP(secret, code)
+	if err != nil {
+		return nil, err
+	}
+
+	recovery = make([]string, recoveryCodesCount)
+	hashes := make([]string, recoveryCodesCount)
+	for i := range recovery {
+		recovery[i], err = newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		hashes[i] = hashRecoveryCode(recovery[i])
+	}
+	err = s.repo.EnableTwoFactor(ctx, usr, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return recovery, nil
+}
+
+// DisableTwoFactor выключает 2FA, если пользователь подтвердил это кодом или кодом восстановления
+func (s TwoFactorService) DisableTwoFactor(ctx context.Context, usr user.User, code string) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		err := s.VerifyTwoFactor(ctx, usr, code)
+		if err != nil {
+			return err
+		}
+		return s.repo.DeleteTwoFactor(ctx, usr)
+	})
+}
+
+// VerifyTwoFactor второй шаг входа: принимает код из приложения или код восстановления
+func (s TwoFactorService) VerifyTwoFactor(ctx context.Context, usr user.User, code string) error {
+	err := s.VerifyTOTP(ctx, usr, code)
+	if err == nil || !errors.Is(err, errors2.ErrTwoFactorCodeIsInvalid) {
+		return err
+	}
+	return s.repo.UseRecoveryCode(ctx, usr, hashRecoveryCode(code))
+}
+
+// VerifyTOTP принимает только код из приложения, например, для подтверждения крупного списания
+func (s TwoFactorService) VerifyTOTP(ctx context.Context, usr user.User, code string) error {
+	secret, enabled, err := s.repo.ReadTwoFactor(ctx, usr)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return errors2.ErrTwoFactorIsNotEnabled
+	}
+	step, err := verifyTOTP(secret, code)
+	if err != nil {
+		return err
+	}
+	return s.repo.UseTwoFactorStep(ctx, usr, step)
+}
+
+func (s TwoFactorService) IsTwoFactorEnabled(ctx context.Context, usr user.User) (bool, error) {
+	_, enabled, err := s.repo.ReadTwoFactor(ctx, usr)
+	if errors.Is(err, errors2.ErrTwoFactorIsNotEnabled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return enabled, nil
+}
+
+func verifyTOTP(secret, code string) (step int64, err error) {
+	key, err := parseTOTPKey(secret)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := key.verify(code, time.Now())
+	if !ok {
+		return 0, errors2.ErrTwoFactorCodeIsInvalid
+	}
+	return step, nil
+}
+
+// newRecoveryCode код вида abcde-fghij, регистр и дефис при вводе не важны
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeLen)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryEncoding.EncodeToString(b))[:recoveryCodeLen]
+	return code[:recoveryCodeLen/2] + "-" + code[recoveryCodeLen/2:], nil
+}
+
+// hashRecoveryCode в хранилище попадает только хеш кода
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	h := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(h[:])
+}
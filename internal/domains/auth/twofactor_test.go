@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mock_auth "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth/mocks"
+	mock_uow "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/uow/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactorService_ConfirmTwoFactor(t *testing.T) {
+	usr := user.User{ID: "1"}
+	key, err := newTOTPKey()
+	require.NoError(t, err)
+	step := totpStep(time.Now())
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	repo := mock_auth.NewMockTwoFactorRepository(mockCtrl)
+	var hashes []string
+	gomock.InOrder(
+		repo.EXPECT().ReadTwoFactor(gomock.Any(), usr).Return(key.String(), false, nil),
+		repo.EXPECT().ReadTwoFactor(gomock.Any(), usr).Return(key.String(), false, nil),
+		repo.EXPECT().EnableTwoFactor(gomock.Any(), usr, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ user.User, s int64, h []string) error {
+				hashes = h
+				return nil
+			}),
+	)
+
+	s := NewTwoFactorService(repo, "test", mock_uow.NewMockUnitOfWork(mockCtrl))
+	_, err = s.ConfirmTwoFactor(context.Background(), usr, "abc")
+	require.ErrorIs(t, err, errors2.ErrTwoFactorCodeIsInvalid)
+
+	recovery, err := s.ConfirmTwoFactor(context.Background(), usr, key.code(step))
+	require.NoError(t, err)
+	require.Len(t, recovery, recoveryCodesCount)
+	require.Len(t, hashes, recoveryCodesCount)
+	for i, code := range recovery {
+		require.Len(t, code, recoveryCodeLen+1)
+		require.Equal(t, hashes[i], hashRecoveryCode(code))
+	}
+}
+
+func TestTwoFactorService_VerifyTwoFactor(t *testing.T) {
+	usr := user.User{ID: "1"}
+	key, err := newTOTPKey()
+	require.NoError(t, err)
+	step := totpStep(time.Now())
+
+	tests := []struct {
+		name    string
+		prepare func(repo *mock_auth.MockTwoFactorRepository)
+		code    string
+		wantErr error
+	}{
+		{
+			name: "two-factor is not enabled",
+			prepare: func(repo *mock_auth.MockTwoFactorRepository) {
+				repo.EXPECT().ReadTwoFactor(gomock.Any(), usr).Return(key.String(), false, nil)
+			},
+			code:    key.code(step),
+			wantErr: errors2.ErrTwoFactorIsNotEnabled,
+		},
+		{
+			name: "totp code",
+			prepare: func(repo *mock_auth.MockTwoFactorRepository) {
+				gomock.InOrder(
+					repo.EXPECT().ReadTwoFactor(gomock.Any(), usr).Return(key.String(), true, nil),
+					repo.EXPECT().UseTwoFactorStep(gomock.Any(), usr, step).Return(nil),
+				)
+			},
+			code: key.code(step),
+		},
+		{
+			name: "totp code is used already",
+			prepare: func(repo *mock_auth.MockTwoFactorRepository) {
+				gomock.InOrder(
+					repo.EXPECT().ReadTwoFactor(gomock.Any(), usr).Return(key.String(), true, nil),
+					repo.EXPECT().UseTwoFactorStep(gomock.Any(), usr, step).Return(errors2.ErrTwoFactorCodeIsInvalid),
+					repo.EXPECT().UseRecoveryCode(gomock.Any(), usr, hashRecoveryCode(key.code(step))).
+						Return(errors2.ErrTwoFactorCodeIsInvalid),
+				)
+			},
+			code:    key.code(step),
+			wantErr: errors2.ErrTwoFactorCodeIsInvalid,
+		},
+		{
+			name: "recovery code",
+			prepare: func(repo *mock_auth.MockTwoFactorRepository) {
+				gomock.InOrder(
+					repo.EXPECT().ReadTwoFactor(gomock.Any(), usr).Return(key.String(), true, nil),
+					repo.EXPECT().UseRecoveryCode(gomock.Any(), usr, hashRecoveryCode("abcdefghij")).Return(nil),
+				)
+			},
+			code: "ABCDE-FGHIJ",
+		},
+		{
+			name: "repository error",
+			prepare: func(repo *mock_auth.MockTwoFactorRepository) {
+				repo.EXPECT().ReadTwoFactor(gomock.Any(), usr).Return("", false, errDummy)
+			},
+			code:    "123456",
+			wantErr: errDummy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			repo := mock_auth.NewMockTwoFactorRepository(mockCtrl)
+			tt.prepare(repo)
+
+			s := NewTwoFactorService(repo, "test", mock_uow.NewMockUnitOfWork(mockCtrl))
+			err := s.VerifyTwoFactor(context.Background(), usr, tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyTwoFactor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
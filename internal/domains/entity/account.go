@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+)
+
+var _ json.Marshaler = (*Account)(nil)
+
+// Account учетная запись пользователя так, как ее видит back-office
+type Account struct {
+	User       user.User
+	Login      string
+	Registered time.Time
+}
+
+func (a *Account) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		ID         string      `json:"id"`
+		Login      string      `json:"login"`
+		Status     user.Status `json:"status"`
+		Role       user.Role   `json:"role"`
+		Registered string      `json:"registered_at"`
+	}{
+		ID:         a.User.ID,
+		Login:      a.Login,
+		Status:     a.User.Status,
+		Role:       a.User.Role,
+		Registered: a.Registered.Format(time.RFC3339),
+	})
+}
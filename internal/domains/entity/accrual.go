@@ -0,0 +1,9 @@
+package entity
+
+import "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+
+// Accrual состояние расчета начислений по заказу во внешней системе
+type Accrual struct {
+	Status  ProcessingStatus
+	Accrual primit.Currency
+}
@@ -36,6 +36,16 @@ func (s ProcessingStatus) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf(`"%s"`, s.String())), nil
 }
 
+// ParseProcessingStatus возвращает статус по его строковому представлению
+func ParseProcessingStatus(str string) (ProcessingStatus, error) {
+	for i, v := range statuses {
+		if v == str {
+			return ProcessingStatus(i), nil
+		}
+	}
+	return New, fmt.Errorf("unknown processing status %q", str)
+}
+
 func (s ProcessingStatus) IsValid() bool {
 	switch s {
 	case New, Processing, Invalid, Processed:
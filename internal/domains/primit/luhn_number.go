@@ -8,6 +8,11 @@ type LuhnNumber uint64
 
 var _ fmt.Stringer = (*LuhnNumber)(nil)
 
+// NewLuhnNumber дописывает к base контрольную цифру по алгоритму Луна
+func NewLuhnNumber(base uint64) LuhnNumber {
+	return LuhnNumber(base*10 + (10-checksum(base))%10)
+}
+
 func (num LuhnNumber) String() string {
 	return fmt.Sprintf("%d", num)
 }
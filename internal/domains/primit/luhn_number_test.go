@@ -67,3 +67,27 @@ func TestNumber_Valid(t *testing.T) {
 		})
 	}
 }
+
+func TestNewLuhnNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		base uint64
+		want LuhnNumber
+	}{
+		{name: "zero", base: 0, want: 0},
+		{name: "one digit", base: 1, want: 18},
+		{name: "two digits", base: 18, want: 182},
+		{name: "long number", base: 1234567890, want: 12345678903},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewLuhnNumber(tt.base)
+			if got != tt.want {
+				t.Errorf("NewLuhnNumber() = %v, want %v", got, tt.want)
+			}
+			if !got.IsValid() {
+				t.Errorf("NewLuhnNumber() = %v is not valid", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/rs/zerolog/log"
+)
+
+//go:generate mockgen -destination=./mocks/mock_accrual.go . AccrualRepository,AccrualGetter
+
+const (
+	pollInterval = 1 * time.Second
+	pollBatch    = 10
+	pollWorkers  = 2
+)
+
+type AccrualRepository interface {
+	// ListUnprocessed выбирает заказы в статусах NEW и PROCESSING, которые дольше всех не проверялись,
+	// и помечает их как проверенные, чтобы другие экземпляры сервиса не взяли их одновременно
+	ListUnprocessed(ctx context.Context, limit int) (ords []entity.Order, err error)
+	UpdateAccrual(ctx context.Context, ord entity.Order) error
+}
+
+type AccrualGetter interface {
+	// Get возвращает состояние расчета начислений по заказу.
+	// Если заказ не зарегистрирован в системе расчета - возвращает ErrAccrualOrderIsNotRegistered,
+	// если система расчета ограничивает количество запросов - ошибку, совместимую с ErrAccrualIsBusy.
+	Get(ctx context.Context, num primit.LuhnNumber) (acc entity.Accrual, err error)
+}
+
+// AccrualPoller синхронизирует статусы заказов с системой расчета начислений
+type AccrualPoller struct {
+	repo     AccrualRepository
+	client   AccrualGetter
+	interval time.Duration
+	batch    int
+	workers  int
+}
+
+func NewAccrualPoller(repo AccrualRepository, client AccrualGetter) *AccrualPoller {
+	if repo == nil {
+		panic("missing AccrualRepository, parameter must not be nil")
+	}
+	if client == nil {
+		panic("missing AccrualGetter, parameter must not be nil")
+	}
+	return &AccrualPoller{
+		repo:     repo,
+		client:   client,
+		interval: pollInterval,
+		batch:    pollBatch,
+		workers:  pollWorkers,
+	}
+}
+
+// Run запускает опрос системы расчета начислений и блокируется до отмены контекста.
+// Возвращает управление только после того, как все воркеры завершили работу.
+func (p *AccrualPoller) Run(ctx context.Context) {
+	jobs := make(chan entity.Order)
+	wg := sync.WaitGroup{}
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ord := range jobs {
+				p.sync(ctx, ord)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer func() {
+		ticker.Stop()
+		close(jobs)
+		wg.Wait()
+		log.Info().Msg("accrual poller stopped")
+	}()
+	log.Info().Msg("accrual poller started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ords, err := p.repo.ListUnprocessed(ctx, p.batch)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Error().Err(err).Msg("can't get orders for accrual processing")
+			}
+			continue
+		}
+		for _, ord := range ords {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- ord:
+			}
+		}
+	}
+}
+
+// sync запрашивает состояние заказа в системе расчета начислений и сохраняет его, если оно изменилось
+func (p *AccrualPoller) sync(ctx context.Context, ord entity.Order) {
+	acc, err := p.client.Get(ctx, ord.Number)
+	switch {
+	case errors.Is(err, errors2.ErrAccrualOrderIsNotRegistered):
+		// система расчета еще не знает о заказе - оставляем его в очереди
+		log.Debug().Msgf("order %s is not registered in accrual system yet", ord.Number)
+		return
+	case errors.Is(err, errors2.ErrAccrualIsBusy):
+		// клиент сам притормозит последующие запросы, заказ будет проверен на следующем круге
+		log.Warn().Err(err).Msgf("accrual system throttled request for order %s", ord.Number)
+		return
+	case err != nil:
+		if ctx.Err() == nil {
+			log.Error().Err(err).Msgf("can't get accrual for order %s", ord.Number)
+		}
+		return
+	}
+
+	ord, changed := applyAccrual(ord, acc)
+	if !changed {
+		return
+	}
+	err = p.repo.UpdateAccrual(ctx, ord)
+	if err != nil {
+		log.Error().Err(err).Msgf("can't update accrual for order %s", ord.Number)
+	}
+}
+
+// Recheck сверяет заказ с системой расчета начислений вне очереди. В отличие от опроса в фоне,
+// сообщает о результате вызывающему. Заказ в конечном статусе не сверяется - возвращает errors.ErrOrderIsProcessedAlready:
+// баллы начисляются триггером только при переходе в PROCESSED, и правка начисления разошлась бы с балансом.
+func (p *AccrualPoller) Recheck(ctx context.Context, ord entity.Order) (entity.Order, error) {
+	if ord.Status == entity.Processed || ord.Status == entity.Invalid {
+		return entity.Order{}, errors2.ErrOrderIsProcessedAlready
+	}
+	acc, err := p.client.Get(ctx, ord.Number)
+	if err != nil {
+		return entity.Order{}, err
+	}
+
+	ord, changed := applyAccrual(ord, acc)
+	if !changed {
+		return ord, nil
+	}
+	err = p.repo.UpdateAccrual(ctx, ord)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	return ord, nil
+}
+
+// applyAccrual переносит состояние расчета в заказ. changed - заказ изменился и его нужно сохранить
+func applyAccrual(ord entity.Order, acc entity.Accrual) (upd entity.Order, changed bool) {
+	if acc.Status == ord.Status && acc.Accrual == ord.Accrual {
+		return ord, false
+	}
+	ord.Status = acc.Status
+	ord.Accrual = acc.Accrual
+	return ord, true
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	mock_service "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service/mocks"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+)
+
+func TestAccrualPoller_sync(t *testing.T) {
+	type fields struct {
+		repo   *mock_service.MockAccrualRepository
+		client *mock_service.MockAccrualGetter
+	}
+	tests := []struct {
+		name    string
+		ord     entity.Order
+		prepare func(f *fields)
+	}{
+		{
+			name: "accrual system error",
+			ord:  entity.Order{ID: "1", Number: 12345678903, Status: entity.New},
+			prepare: func(f *fields) {
+				f.client.EXPECT().Get(context.Background(), gomock.Any()).Return(entity.Accrual{}, errDummy)
+			},
+		},
+		{
+			name: "order is not registered yet",
+			ord:  entity.Order{ID: "1", Number: 12345678903, Status: entity.New},
+			prepare: func(f *fields) {
+				f.client.EXPECT().Get(context.Background(), gomock.Any()).Return(entity.Accrual{}, errors2.ErrAccrualOrderIsNotRegistered)
+			},
+		},
+		{
+			name: "accrual system throttles requests",
+			ord:  entity.Order{ID: "1", Number: 12345678903, Status: entity.New},
+			prepare: func(f *fields) {
+				f.client.EXPECT().Get(context.Background(), gomock.Any()).Return(entity.Accrual{}, errors2.ErrAccrualIsBusy)
+			},
+		},
+		{
+			name: "status is not changed",
+			ord:  entity.Order{ID: "1", Number: 12345678903, Status: entity.Processing},
+			prepare: func(f *fields) {
+				f.client.EXPECT().Get(context.Background(), gomock.Any()).Return(entity.Accrual{Status: entity.Processing}, nil)
+			},
+		},
+		{
+			name: "registered order goes to processing",
+			ord:  entity.Order{ID: "1", Number: 12345678903, Status: entity.New},
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.client.EXPECT().Get(context.Background(), gomock.Any()).Return(entity.Accrual{Status: entity.Processing}, nil),
+					f.repo.EXPECT().UpdateAccrual(context.Background(),
+						entity.Order{ID: "1", Number: 12345678903, Status: entity.Processing}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "processed order with accrual",
+			ord:  entity.Order{ID: "1", Number: 12345678903, Status: entity.Processing},
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.client.EXPECT().Get(context.Background(), gomock.Any()).Return(entity.Accrual{Status: entity.Processed, Accrual: 50050}, nil),
+					f.repo.EXPECT().UpdateAccrual(context.Background(),
+						entity.Order{ID: "1", Number: 12345678903, Status: entity.Processed, Accrual: 50050}).Return(nil),
+				)
+			},
+		},
+		{
+			name: "invalid order",
+			ord:  entity.Order{ID: "1", Number: 12345678903, Status: entity.New},
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.client.EXPECT().Get(context.Background(), gomock.Any()).Return(entity.Accrual{Status: entity.Invalid}, nil),
+					f.repo.EXPECT().UpdateAccrual(context.Background(),
+						entity.Order{ID: "1", Number: 12345678903, Status: entity.Invalid}).Return(errDummy),
+				)
+			},
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			f := fields{
+				repo:   mock_service.NewMockAccrualRepository(mockCtrl),
+				client: mock_service.NewMockAccrualGetter(mockCtrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			p := NewAccrualPoller(f.repo, f.client)
+			p.sync(context.Background(), tt.ord)
+		})
+	}
+}
+
+func TestAccrualPoller_Recheck(t *testing.T) {
+	processing := entity.Order{ID: "1", Number: 12345678903, Status: entity.Processing}
+	tests := []struct {
+		name    string
+		ord     entity.Order
+		prepare func(repo *mock_service.MockAccrualRepository, client *mock_service.MockAccrualGetter)
+		want    entity.Order
+		wantErr error
+	}{
+		{
+			name: "order is not registered",
+			ord:  processing,
+			prepare: func(repo *mock_service.MockAccrualRepository, client *mock_service.MockAccrualGetter) {
+				client.EXPECT().Get(gomock.Any(), processing.Number).Return(entity.Accrual{}, errors2.ErrAccrualOrderIsNotRegistered)
+			},
+			wantErr: errors2.ErrAccrualOrderIsNotRegistered,
+		},
+		{
+			name: "accrual system throttles requests",
+			ord:  processing,
+			prepare: func(repo *mock_service.MockAccrualRepository, client *mock_service.MockAccrualGetter) {
+				client.EXPECT().Get(gomock.Any(), processing.Number).Return(entity.Accrual{}, errors2.ErrAccrualIsBusy)
+			},
+			wantErr: errors2.ErrAccrualIsBusy,
+		},
+		{
+			name: "order is not changed",
+			ord:  processing,
+			prepare: func(repo *mock_service.MockAccrualRepository, client *mock_service.MockAccrualGetter) {
+				client.EXPECT().Get(gomock.Any(), processing.Number).Return(entity.Accrual{Status: entity.Processing}, nil)
+			},
+			want: processing,
+		},
+		{
+			name: "order is processed",
+			ord:  processing,
+			prepare: func(repo *mock_service.MockAccrualRepository, client *mock_service.MockAccrualGetter) {
+				client.EXPECT().Get(gomock.Any(), processing.Number).
+					Return(entity.Accrual{Status: entity.Processed, Accrual: 700}, nil)
+				repo.EXPECT().UpdateAccrual(gomock.Any(),
+					entity.Order{ID: "1", Number: 12345678903, Status: entity.Processed, Accrual: 700}).Return(nil)
+			},
+			want: entity.Order{ID: "1", Number: 12345678903, Status: entity.Processed, Accrual: 700},
+		},
+		{
+			name:    "processed order is not rechecked",
+			ord:     entity.Order{ID: "1", Number: 12345678903, Status: entity.Processed, Accrual: 500},
+			prepare: func(repo *mock_service.MockAccrualRepository, client *mock_service.MockAccrualGetter) {},
+			wantErr: errors2.ErrOrderIsProcessedAlready,
+		},
+		{
+			name:    "invalid order is not rechecked",
+			ord:     entity.Order{ID: "1", Number: 12345678903, Status: entity.Invalid},
+			prepare: func(repo *mock_service.MockAccrualRepository, client *mock_service.MockAccrualGetter) {},
+			wantErr: errors2.ErrOrderIsProcessedAlready,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			repo := mock_service.NewMockAccrualRepository(mockCtrl)
+			client := mock_service.NewMockAccrualGetter(mockCtrl)
+			tt.prepare(repo, client)
+
+			got, err := NewAccrualPoller(repo, client).Recheck(context.Background(), tt.ord)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Recheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Recheck() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
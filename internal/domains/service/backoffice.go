@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+)
+
+//go:generate mockgen -destination=./mocks/mock_backoffice.go . UserDirectory
+
+const (
+	DefaultUserSearchLimit = 50
+	MaxUserSearchLimit     = 500
+)
+
+type UserDirectory interface {
+	// FindByLogin ищет учетные записи, в логине которых встречается подстрока login, без учета регистра.
+	// Учетные записи отсортированы по логину
+	FindByLogin(ctx context.Context, login string, limit int) (accs []entity.Account, err error)
+}
+
+var _ app.BackOffice = (*BackOffice)(nil)
+
+// BackOffice инструменты поддержки: поиск пользователей и ручная сверка заказов с системой расчета
+type BackOffice struct {
+	users  UserDirectory
+	orders OrderRepository
+	poller *AccrualPoller
+}
+
+func NewBackOffice(users UserDirectory, orders OrderRepository, poller *AccrualPoller) *BackOffice {
+	if users == nil {
+		panic("missing UserDirectory, parameter must not be nil")
+	}
+	if orders == nil {
+		panic("missing OrderRepository, parameter must not be nil")
+	}
+	if poller == nil {
+		panic("missing *AccrualPoller, parameter must not be nil")
+	}
+	return &BackOffice{users: users, orders: orders, poller: poller}
+}
+
+// FindUsers limit вне диапазона от 1 до MaxUserSearchLimit заменяется на DefaultUserSearchLimit
+func (b BackOffice) FindUsers(ctx context.Context, login string, limit int) (accs []entity.Account, err error) {
+	if limit <= 0 || limit > MaxUserSearchLimit {
+		limit = DefaultUserSearchLimit
+	}
+	return b.users.FindByLogin(ctx, login, limit)
+}
+
+func (b BackOffice) RecheckOrder(ctx context.Context, num string) (ord entity.Order, err error) {
+	number, err := ParseLuhnNumber(num)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	ord, err = b.orders.Read(ctx, number)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	return b.poller.Recheck(ctx, ord)
+}
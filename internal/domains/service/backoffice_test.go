@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	mock_service "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service/mocks"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang/mock/gomock"
+)
+
+func TestBackOffice_FindUsers(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit int
+	}{
+		{name: "default limit", limit: 0, wantLimit: DefaultUserSearchLimit},
+		{name: "limit is too big", limit: MaxUserSearchLimit + 1, wantLimit: DefaultUserSearchLimit},
+		{name: "limit is set", limit: 10, wantLimit: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			users := mock_service.NewMockUserDirectory(mockCtrl)
+			users.EXPECT().FindByLogin(gomock.Any(), "bob", tt.wantLimit).Return(nil, nil)
+
+			poller := NewAccrualPoller(mock_service.NewMockAccrualRepository(mockCtrl), mock_service.NewMockAccrualGetter(mockCtrl))
+			b := NewBackOffice(users, mock_service.NewMockOrderRepository(mockCtrl), poller)
+			_, err := b.FindUsers(context.Background(), "bob", tt.limit)
+			if err != nil {
+				t.Errorf("FindUsers() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestBackOffice_RecheckOrder(t *testing.T) {
+	ord := entity.Order{ID: "1", Number: 12345678903, Status: entity.New}
+	tests := []struct {
+		name    string
+		num     string
+		prepare func(orders *mock_service.MockOrderRepository, client *mock_service.MockAccrualGetter)
+		wantErr error
+	}{
+		{
+			name:    "invalid number",
+			num:     "12345678900",
+			wantErr: errors2.ErrOrderInvalidNumberFormat,
+		},
+		{
+			name: "order is not found",
+			num:  "12345678903",
+			prepare: func(orders *mock_service.MockOrderRepository, client *mock_service.MockAccrualGetter) {
+				orders.EXPECT().Read(gomock.Any(), primit.LuhnNumber(12345678903)).Return(entity.Order{}, errors2.ErrOrderNotFound)
+			},
+			wantErr: errors2.ErrOrderNotFound,
+		},
+		{
+			name: "order is rechecked",
+			num:  "12345678903",
+			prepare: func(orders *mock_service.MockOrderRepository, client *mock_service.MockAccrualGetter) {
+				gomock.InOrder(
+					orders.EXPECT().Read(gomock.Any(), primit.LuhnNumber(12345678903)).Return(ord, nil),
+					client.EXPECT().Get(gomock.Any(), ord.Number).Return(entity.Accrual{Status: entity.Processing}, nil),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			orders := mock_service.NewMockOrderRepository(mockCtrl)
+			repo := mock_service.NewMockAccrualRepository(mockCtrl)
+			client := mock_service.NewMockAccrualGetter(mockCtrl)
+			if tt.prepare != nil {
+				tt.prepare(orders, client)
+			}
+			repo.EXPECT().UpdateAccrual(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+			b := NewBackOffice(mock_service.NewMockUserDirectory(mockCtrl), orders, NewAccrualPoller(repo, client))
+			_, err := b.RecheckOrder(context.Background(), tt.num)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RecheckOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+)
+
+//go:generate mockgen -destination=./mocks/mock_balance.go . BalanceRepository
+
+type BalanceRepository interface {
+	// Read возвращает сумму начисленных (Collected) и списанных (Withdrawn) баллов пользователя
+	Read(ctx context.Context, usr user.User) (bal entity.Balance, err error)
+}
+
+var _ app.BalanceGetter = (*Balance)(nil)
+
+type Balance struct {
+	repo BalanceRepository
+}
+
+func NewBalance(repo BalanceRepository) *Balance {
+	if repo == nil {
+		panic("missing BalanceRepository, parameter must not be nil")
+	}
+	return &Balance{repo: repo}
+}
+
+func (b Balance) Get(ctx context.Context, usr user.User) (bal entity.Balance, err error) {
+	bal, err = b.repo.Read(ctx, usr)
+	if err != nil {
+		return entity.Balance{}, err
+	}
+	bal.Current = bal.Collected - bal.Withdrawn
+	return bal, nil
+}
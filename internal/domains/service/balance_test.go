@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	mock_service "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalance_Get(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		stored  entity.Balance
+		readErr error
+		want    entity.Balance
+		wantErr bool
+	}{
+		{
+			name:   "empty balance",
+			stored: entity.Balance{User: usr},
+			want:   entity.Balance{User: usr},
+		},
+		{
+			name:   "current is collected minus withdrawn",
+			stored: entity.Balance{User: usr, Collected: 72955, Withdrawn: 4200},
+			want:   entity.Balance{User: usr, Current: 68755, Collected: 72955, Withdrawn: 4200},
+		},
+		{
+			name:    "repository error",
+			readErr: errDummy,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			repo := mock_service.NewMockBalanceRepository(mockCtrl)
+			repo.EXPECT().Read(context.Background(), usr).Return(tt.stored, tt.readErr)
+
+			b := NewBalance(repo)
+			got, err := b.Get(context.Background(), usr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service (interfaces: AccrualRepository,AccrualGetter)
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	primit "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAccrualRepository is a mock of AccrualRepository interface.
+type MockAccrualRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccrualRepositoryMockRecorder
+}
+
+// MockAccrualRepositoryMockRecorder is the mock recorder for MockAccrualRepository.
+type MockAccrualRepositoryMockRecorder struct {
+	mock *MockAccrualRepository
+}
+
+// NewMockAccrualRepository creates a new mock instance.
+func NewMockAccrualRepository(ctrl *gomock.Controller) *MockAccrualRepository {
+	mock := &MockAccrualRepository{ctrl: ctrl}
+	mock.recorder = &MockAccrualRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccrualRepository) EXPECT() *MockAccrualRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListUnprocessed mocks base method.
+func (m *MockAccrualRepository) ListUnprocessed(arg0 context.Context, arg1 int) ([]entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnprocessed", arg0, arg1)
+	ret0, _ := ret[0].([]entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnprocessed indicates an expected call of ListUnprocessed.
+func (mr *MockAccrualRepositoryMockRecorder) ListUnprocessed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnprocessed", reflect.TypeOf((*MockAccrualRepository)(nil).ListUnprocessed), arg0, arg1)
+}
+
+// UpdateAccrual mocks base method.
+func (m *MockAccrualRepository) UpdateAccrual(arg0 context.Context, arg1 entity.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccrual", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccrual indicates an expected call of UpdateAccrual.
+func (mr *MockAccrualRepositoryMockRecorder) UpdateAccrual(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccrual", reflect.TypeOf((*MockAccrualRepository)(nil).UpdateAccrual), arg0, arg1)
+}
+
+// MockAccrualGetter is a mock of AccrualGetter interface.
+type MockAccrualGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccrualGetterMockRecorder
+}
+
+// MockAccrualGetterMockRecorder is the mock recorder for MockAccrualGetter.
+type MockAccrualGetterMockRecorder struct {
+	mock *MockAccrualGetter
+}
+
+// NewMockAccrualGetter creates a new mock instance.
+func NewMockAccrualGetter(ctrl *gomock.Controller) *MockAccrualGetter {
+	mock := &MockAccrualGetter{ctrl: ctrl}
+	mock.recorder = &MockAccrualGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccrualGetter) EXPECT() *MockAccrualGetterMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAccrualGetter) Get(arg0 context.Context, arg1 primit.LuhnNumber) (entity.Accrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(entity.Accrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAccrualGetterMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAccrualGetter)(nil).Get), arg0, arg1)
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service (interfaces: BalanceAdjuster,OrderRequeuer)
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	primit "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	user "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBalanceAdjuster is a mock of BalanceAdjuster interface.
+type MockBalanceAdjuster struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceAdjusterMockRecorder
+}
+
+// MockBalanceAdjusterMockRecorder is the mock recorder for MockBalanceAdjuster.
+type MockBalanceAdjusterMockRecorder struct {
+	mock *MockBalanceAdjuster
+}
+
+// NewMockBalanceAdjuster creates a new mock instance.
+func NewMockBalanceAdjuster(ctrl *gomock.Controller) *MockBalanceAdjuster {
+	mock := &MockBalanceAdjuster{ctrl: ctrl}
+	mock.recorder = &MockBalanceAdjusterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceAdjuster) EXPECT() *MockBalanceAdjusterMockRecorder {
+	return m.recorder
+}
+
+// Adjust mocks base method.
+func (m *MockBalanceAdjuster) Adjust(arg0 context.Context, arg1 user.User, arg2 primit.Currency) (entity.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockBalanceAdjusterMockRecorder) Adjust(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockBalanceAdjuster)(nil).Adjust), arg0, arg1, arg2)
+}
+
+// MockOrderRequeuer is a mock of OrderRequeuer interface.
+type MockOrderRequeuer struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderRequeuerMockRecorder
+}
+
+// MockOrderRequeuerMockRecorder is the mock recorder for MockOrderRequeuer.
+type MockOrderRequeuerMockRecorder struct {
+	mock *MockOrderRequeuer
+}
+
+// NewMockOrderRequeuer creates a new mock instance.
+func NewMockOrderRequeuer(ctrl *gomock.Controller) *MockOrderRequeuer {
+	mock := &MockOrderRequeuer{ctrl: ctrl}
+	mock.recorder = &MockOrderRequeuerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderRequeuer) EXPECT() *MockOrderRequeuerMockRecorder {
+	return m.recorder
+}
+
+// Requeue mocks base method.
+func (m *MockOrderRequeuer) Requeue(arg0 context.Context, arg1 primit.LuhnNumber) (entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Requeue", arg0, arg1)
+	ret0, _ := ret[0].(entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockOrderRequeuerMockRecorder) Requeue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockOrderRequeuer)(nil).Requeue), arg0, arg1)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+)
+
+//go:generate mockgen -destination=./mocks/mock_operator.go . BalanceAdjuster,OrderRequeuer
+
+type BalanceAdjuster interface {
+	// Adjust меняет начисленные пользователю баллы на delta и возвращает новый баланс.
+	// Если начисленных станет меньше, чем списанных - возвращает errors.ErrBalanceAdjustmentIsTooLow
+	Adjust(ctx context.Context, usr user.User, delta primit.Currency) (bal entity.Balance, err error)
+}
+
+type OrderRequeuer interface {
+	// Requeue возвращает заказ в очередь на проверку в системе расчета со статусом NEW.
+	// Если заказа нет - возвращает errors.ErrOrderNotFound, если он уже обработан - errors.ErrOrderIsProcessedAlready
+	Requeue(ctx context.Context, num primit.LuhnNumber) (ord entity.Order, err error)
+}
+
+// Operator ручные правки данных оператором: корректировка баланса и повторная отправка заказа на расчет
+type Operator struct {
+	balances BalanceAdjuster
+	orders   OrderRequeuer
+}
+
+func NewOperator(balances BalanceAdjuster, orders OrderRequeuer) *Operator {
+	if balances == nil {
+		panic("missing BalanceAdjuster, parameter must not be nil")
+	}
+	if orders == nil {
+		panic("missing OrderRequeuer, parameter must not be nil")
+	}
+	return &Operator{balances: balances, orders: orders}
+}
+
+// AdjustBalance delta может быть отрицательной, например, чтобы отменить ошибочное начисление
+func (o Operator) AdjustBalance(ctx context.Context, usr user.User, delta primit.Currency) (bal entity.Balance, err error) {
+	if delta == 0 {
+		return entity.Balance{}, errors2.ErrBalanceAdjustmentIsZero
+	}
+	bal, err = o.balances.Adjust(ctx, usr, delta)
+	if err != nil {
+		return entity.Balance{}, err
+	}
+	bal.Current = bal.Collected - bal.Withdrawn
+	return bal, nil
+}
+
+// RequeueOrder обработанный заказ в очередь не возвращается, иначе баллы по нему начислились бы повторно
+func (o Operator) RequeueOrder(ctx context.Context, num string) (ord entity.Order, err error) {
+	number, err := ParseLuhnNumber(num)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	return o.orders.Requeue(ctx, number)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	mock_service "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang/mock/gomock"
+)
+
+func TestOperator_AdjustBalance(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		delta   primit.Currency
+		prepare func(balances *mock_service.MockBalanceAdjuster)
+		want    entity.Balance
+		wantErr error
+	}{
+		{
+			name:    "zero delta",
+			delta:   0,
+			wantErr: errors2.ErrBalanceAdjustmentIsZero,
+		},
+		{
+			name:  "balance is adjusted",
+			delta: -500,
+			prepare: func(balances *mock_service.MockBalanceAdjuster) {
+				balances.EXPECT().Adjust(gomock.Any(), usr, primit.Currency(-500)).
+					Return(entity.Balance{User: usr, Collected: 1500, Withdrawn: 1000}, nil)
+			},
+			want: entity.Balance{User: usr, Collected: 1500, Withdrawn: 1000, Current: 500},
+		},
+		{
+			name:  "balance is too low",
+			delta: -500,
+			prepare: func(balances *mock_service.MockBalanceAdjuster) {
+				balances.EXPECT().Adjust(gomock.Any(), usr, primit.Currency(-500)).
+					Return(entity.Balance{}, errors2.ErrBalanceAdjustmentIsTooLow)
+			},
+			wantErr: errors2.ErrBalanceAdjustmentIsTooLow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			balances := mock_service.NewMockBalanceAdjuster(mockCtrl)
+			if tt.prepare != nil {
+				tt.prepare(balances)
+			}
+
+			o := NewOperator(balances, mock_service.NewMockOrderRequeuer(mockCtrl))
+			got, err := o.AdjustBalance(context.Background(), usr, tt.delta)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AdjustBalance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AdjustBalance() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperator_RequeueOrder(t *testing.T) {
+	ord := entity.Order{ID: "1", Number: 12345678903, Status: entity.New}
+	tests := []struct {
+		name    string
+		num     string
+		prepare func(orders *mock_service.MockOrderRequeuer)
+		want    entity.Order
+		wantErr error
+	}{
+		{
+			name:    "invalid number",
+			num:     "12345678900",
+			wantErr: errors2.ErrOrderInvalidNumberFormat,
+		},
+		{
+			name: "order is processed already",
+			num:  "12345678903",
+			prepare: func(orders *mock_service.MockOrderRequeuer) {
+				orders.EXPECT().Requeue(gomock.Any(), primit.LuhnNumber(12345678903)).
+					Return(entity.Order{}, errors2.ErrOrderIsProcessedAlready)
+			},
+			wantErr: errors2.ErrOrderIsProcessedAlready,
+		},
+		{
+			name: "order is requeued",
+			num:  "12345678903",
+			prepare: func(orders *mock_service.MockOrderRequeuer) {
+				orders.EXPECT().Requeue(gomock.Any(), primit.LuhnNumber(12345678903)).Return(ord, nil)
+			},
+			want: ord,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			orders := mock_service.NewMockOrderRequeuer(mockCtrl)
+			if tt.prepare != nil {
+				tt.prepare(orders)
+			}
+
+			o := NewOperator(mock_service.NewMockBalanceAdjuster(mockCtrl), orders)
+			got, err := o.RequeueOrder(context.Background(), tt.num)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RequeueOrder() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RequeueOrder() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
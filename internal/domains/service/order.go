@@ -2,23 +2,79 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"strings"
 
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	_ "github.com/golang/mock/mockgen/model"
 )
 
+//go:generate mockgen -destination=./mocks/mock_order.go . OrderRepository
+
+type OrderRepository interface {
+	// Create сохраняет новый заказ.
+	// Если заказ с таким номером уже есть - возвращает errors.ErrOrderAlreadyUploaded
+	Create(ctx context.Context, ord entity.Order) error
+	// Read если заказа нет - возвращает errors.ErrOrderNotFound
+	Read(ctx context.Context, num primit.LuhnNumber) (ord entity.Order, err error)
+	// List возвращает заказы пользователя, отсортированные по времени загрузки от самых старых к самым новым
+	List(ctx context.Context, usr user.User) (ords []entity.Order, err error)
+}
+
 var _ app.OrderProcessor = (*Order)(nil)
 
 type Order struct {
+	repo OrderRepository
+}
+
+func NewOrder(repo OrderRepository) *Order {
+	if repo == nil {
+		panic("missing OrderRepository, parameter must not be nil")
+	}
+	return &Order{repo: repo}
 }
 
+// Add регистрирует номер заказа за пользователем.
+// Если номер уже загружен, то различает, кем именно он был загружен: этим же пользователем или другим.
 func (o Order) Add(ctx context.Context, usr user.User, num string) error {
-	// TODO implement me
-	panic("implement me")
+	number, err := ParseLuhnNumber(num)
+	if err != nil {
+		return err
+	}
+
+	err = o.repo.Create(ctx, entity.Order{User: usr, Number: number, Status: entity.New})
+	if !errors.Is(err, errors2.ErrOrderAlreadyUploaded) {
+		return err
+	}
+
+	ord, err := o.repo.Read(ctx, number)
+	if err != nil {
+		return err
+	}
+	if ord.User.ID != usr.ID {
+		return errors2.ErrOrderAlreadyUploadedByAnotherUser
+	}
+	return errors2.ErrOrderAlreadyUploaded
 }
 
 func (o Order) List(ctx context.Context, usr user.User) (ords []entity.Order, err error) {
-	// TODO implement me
-	panic("implement me")
+	return o.repo.List(ctx, usr)
+}
+
+// ParseLuhnNumber разбирает строку с номером заказа и проверяет его контрольную сумму по алгоритму Луна
+func ParseLuhnNumber(num string) (primit.LuhnNumber, error) {
+	n, err := strconv.ParseUint(strings.TrimSpace(num), 10, 64)
+	if err != nil {
+		return 0, errors2.ErrOrderInvalidNumberFormat
+	}
+	number := primit.LuhnNumber(n)
+	if !number.IsValid() {
+		return 0, errors2.ErrOrderInvalidNumberFormat
+	}
+	return number, nil
 }
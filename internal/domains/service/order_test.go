@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	mock_service "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang/mock/gomock"
+)
+
+var errDummy = errors.New("dummy error")
+
+func TestOrder_Add(t *testing.T) {
+	type fields struct {
+		repo *mock_service.MockOrderRepository
+	}
+	type args struct {
+		usr user.User
+		num string
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		args    args
+		wantErr error
+	}{
+		{
+			name: "not a number",
+			args: args{
+				usr: user.User{ID: "1"},
+				num: "12345abc",
+			},
+			wantErr: errors2.ErrOrderInvalidNumberFormat,
+		},
+		{
+			name: "invalid checksum",
+			args: args{
+				usr: user.User{ID: "1"},
+				num: "12345678901",
+			},
+			wantErr: errors2.ErrOrderInvalidNumberFormat,
+		},
+		{
+			name: "new order",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.repo.EXPECT().Create(context.Background(), entity.Order{User: user.User{ID: "1"}, Number: 12345678903}).Return(nil),
+				)
+			},
+			args: args{
+				usr: user.User{ID: "1"},
+				num: "12345678903\n",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "already uploaded by this user",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.repo.EXPECT().Create(context.Background(), gomock.Any()).Return(errors2.ErrOrderAlreadyUploaded),
+					f.repo.EXPECT().Read(context.Background(), gomock.Any()).Return(entity.Order{User: user.User{ID: "1"}}, nil),
+				)
+			},
+			args: args{
+				usr: user.User{ID: "1"},
+				num: "12345678903",
+			},
+			wantErr: errors2.ErrOrderAlreadyUploaded,
+		},
+		{
+			name: "already uploaded by another user",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.repo.EXPECT().Create(context.Background(), gomock.Any()).Return(errors2.ErrOrderAlreadyUploaded),
+					f.repo.EXPECT().Read(context.Background(), gomock.Any()).Return(entity.Order{User: user.User{ID: "2"}}, nil),
+				)
+			},
+			args: args{
+				usr: user.User{ID: "1"},
+				num: "12345678903",
+			},
+			wantErr: errors2.ErrOrderAlreadyUploadedByAnotherUser,
+		},
+		{
+			name: "can't create order",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.repo.EXPECT().Create(context.Background(), gomock.Any()).Return(errDummy),
+				)
+			},
+			args: args{
+				usr: user.User{ID: "1"},
+				num: "12345678903",
+			},
+			wantErr: errDummy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			f := fields{
+				repo: mock_service.NewMockOrderRepository(mockCtrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			o := NewOrder(f.repo)
+			if err := o.Add(context.Background(), tt.args.usr, tt.args.num); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+)
+
+//go:generate mockgen -destination=./mocks/mock_withdrawal.go . WithdrawalRepository
+
+type WithdrawalRepository interface {
+	// Create атомарно проверяет остаток на счете пользователя и списывает с него сумму.
+	// Если средств недостаточно - возвращает errors.ErrWithdrawalNotEnoughFund
+	Create(ctx context.Context, wd entity.Withdrawal) error
+	// List возвращает списания пользователя, отсортированные по времени списания от самых старых к самым новым
+	List(ctx context.Context, usr user.User) (wtdrwls []entity.Withdrawal, err error)
+}
+
+var _ app.WithdrawalProcessor = (*Withdrawal)(nil)
+
+type Withdrawal struct {
+	repo WithdrawalRepository
+}
+
+func NewWithdrawal(repo WithdrawalRepository) *Withdrawal {
+	if repo == nil {
+		panic("missing WithdrawalRepository, parameter must not be nil")
+	}
+	return &Withdrawal{repo: repo}
+}
+
+func (w Withdrawal) Add(ctx context.Context, usr user.User, num string, sum primit.Currency) error {
+	number, err := ParseLuhnNumber(num)
+	if err != nil {
+		return err
+	}
+	if sum <= 0 {
+		return errors2.ErrWithdrawalInvalidSum
+	}
+	return w.repo.Create(ctx, entity.Withdrawal{
+		User:  usr,
+		Order: entity.Order{User: usr, Number: number},
+		Sum:   sum,
+	})
+}
+
+func (w Withdrawal) List(ctx context.Context, usr user.User) (wtdrwls []entity.Withdrawal, err error) {
+	return w.repo.List(ctx, usr)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	mock_service "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang/mock/gomock"
+)
+
+func TestWithdrawal_Add(t *testing.T) {
+	usr := user.User{ID: "1"}
+	type args struct {
+		num string
+		sum primit.Currency
+	}
+	tests := []struct {
+		name    string
+		prepare func(repo *mock_service.MockWithdrawalRepository)
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "invalid order number",
+			args:    args{num: "2377225625", sum: 75100},
+			wantErr: errors2.ErrOrderInvalidNumberFormat,
+		},
+		{
+			name:    "zero sum",
+			args:    args{num: "2377225624", sum: 0},
+			wantErr: errors2.ErrWithdrawalInvalidSum,
+		},
+		{
+			name:    "negative sum",
+			args:    args{num: "2377225624", sum: -100},
+			wantErr: errors2.ErrWithdrawalInvalidSum,
+		},
+		{
+			name: "not enough fund",
+			prepare: func(repo *mock_service.MockWithdrawalRepository) {
+				repo.EXPECT().Create(context.Background(), gomock.Any()).Return(errors2.ErrWithdrawalNotEnoughFund)
+			},
+			args:    args{num: "2377225624", sum: 75100},
+			wantErr: errors2.ErrWithdrawalNotEnoughFund,
+		},
+		{
+			name: "withdrawn",
+			prepare: func(repo *mock_service.MockWithdrawalRepository) {
+				repo.EXPECT().Create(context.Background(), entity.Withdrawal{
+					User:  usr,
+					Order: entity.Order{User: usr, Number: 2377225624},
+					Sum:   75100,
+				}).Return(nil)
+			},
+			args:    args{num: "2377225624", sum: 75100},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			repo := mock_service.NewMockWithdrawalRepository(mockCtrl)
+			if tt.prepare != nil {
+				tt.prepare(repo)
+			}
+
+			w := NewWithdrawal(repo)
+			if err := w.Add(context.Background(), usr, tt.args.num, tt.args.sum); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
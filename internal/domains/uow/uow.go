@@ -0,0 +1,18 @@
+package uow
+
+import (
+	"context"
+
+	_ "github.com/golang/mock/mockgen/model"
+)
+
+//go:generate mockgen -destination=./mocks/mock_uow.go . UnitOfWork
+
+// UnitOfWork позволяет доменным сервисам выполнить несколько обращений к разным репозиториям как одно атомарное действие
+type UnitOfWork interface {
+	// Do выполняет fn в транзакции.
+	// Репозитории, вызванные с контекстом, переданным в fn, работают в рамках этой транзакции.
+	// Если fn вернула ошибку - транзакция откатывается, иначе - фиксируется.
+	// Вложенный вызов Do выполняется в рамках внешней транзакции.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -85,3 +85,32 @@ func (mr *MockRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0, arg1)
 }
+
+// ReadRole mocks base method.
+func (m *MockRepository) ReadRole(arg0 context.Context, arg1 user.User) (user.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadRole", arg0, arg1)
+	ret0, _ := ret[0].(user.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadRole indicates an expected call of ReadRole.
+func (mr *MockRepositoryMockRecorder) ReadRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRole", reflect.TypeOf((*MockRepository)(nil).ReadRole), arg0, arg1)
+}
+
+// UpdateRole mocks base method.
+func (m *MockRepository) UpdateRole(arg0 context.Context, arg1 user.User, arg2 user.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockRepositoryMockRecorder) UpdateRole(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockRepository)(nil).UpdateRole), arg0, arg1, arg2)
+}
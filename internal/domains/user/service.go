@@ -14,6 +14,10 @@ type Registerer interface {
 
 type Repository interface {
 	Create(ctx context.Context, user User) error
+	// ReadRole если пользователь не найден, отключен или удален - возвращает errors.ErrUserNotFound
+	ReadRole(ctx context.Context, user User) (Role, error)
+	// UpdateRole если пользователь не найден или удален - возвращает errors.ErrUserNotFound
+	UpdateRole(ctx context.Context, user User, role Role) error
 }
 
 var _ Registerer = (*Service)(nil)
@@ -32,3 +36,16 @@ func NewService(repo Repository) *Service {
 func (s *Service) RegisterNewUser(ctx context.Context, user User) error {
 	return s.repo.Create(ctx, user)
 }
+
+// UserRole роль пользователя читается на каждый запрос, поэтому смена роли действует сразу, без нового входа
+func (s *Service) UserRole(ctx context.Context, user User) (Role, error) {
+	return s.repo.ReadRole(ctx, user)
+}
+
+func (s *Service) ChangeRole(ctx context.Context, user User, role Role) error {
+	role, err := ParseRole(string(role))
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateRole(ctx, user, role)
+}
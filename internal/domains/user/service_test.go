@@ -0,0 +1,58 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	mock_user "github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user/mocks"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang/mock/gomock"
+)
+
+func TestService_ChangeRole(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		role    user.Role
+		prepare func(repo *mock_user.MockRepository)
+		wantErr error
+	}{
+		{
+			name:    "unknown role",
+			role:    "root",
+			wantErr: errors2.ErrRoleIsInvalid,
+		},
+		{
+			name: "role is normalized",
+			role: "support",
+			prepare: func(repo *mock_user.MockRepository) {
+				repo.EXPECT().UpdateRole(gomock.Any(), usr, user.RoleSupport).Return(nil)
+			},
+		},
+		{
+			name: "user is not found",
+			role: user.RoleAdmin,
+			prepare: func(repo *mock_user.MockRepository) {
+				repo.EXPECT().UpdateRole(gomock.Any(), usr, user.RoleAdmin).Return(errors2.ErrUserNotFound)
+			},
+			wantErr: errors2.ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			repo := mock_user.NewMockRepository(mockCtrl)
+			if tt.prepare != nil {
+				tt.prepare(repo)
+			}
+
+			err := user.NewService(repo).ChangeRole(context.Background(), usr, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ChangeRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
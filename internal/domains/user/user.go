@@ -1,18 +1,54 @@
 package user
 
 import (
-	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"strings"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
 	"github.com/google/uuid"
 )
 
-var _ middleware.Referencer = (*User)(nil)
+var _ access.Referencer = (*User)(nil)
+
+// Status состояние учетной записи пользователя
+type Status string
+
+const (
+	Active Status = "ACTIVE"
+	// Disabled вход запрещен, данные и история сохраняются
+	Disabled Status = "DISABLED"
+	// Deleted креды удалены, персональные данные обезличены, финансовая история сохраняется
+	Deleted Status = "DELETED"
+)
+
+// Role определяет, что пользователю разрешено делать кроме работы со своими заказами и баллами
+type Role string
+
+const (
+	RoleUser Role = "USER"
+	// RoleSupport сотрудник поддержки, back-office только на чтение
+	RoleSupport Role = "SUPPORT"
+	RoleAdmin   Role = "ADMIN"
+)
+
+// ParseRole регистр не важен
+func ParseRole(s string) (Role, error) {
+	role := Role(strings.ToUpper(s))
+	switch role {
+	case RoleUser, RoleSupport, RoleAdmin:
+		return role, nil
+	}
+	return "", errors2.ErrRoleIsInvalid
+}
 
 type User struct {
-	ID string
+	ID     string
+	Status Status
+	Role   Role
 }
 
 func NewUser() User {
-	return User{ID: uuid.New().String()}
+	return User{ID: uuid.New().String(), Status: Active, Role: RoleUser}
 }
 
 func (u User) Reference() string {
@@ -8,10 +8,60 @@ var (
 	ErrPairLoginPwordIsNotExist = errors.New("given pair login and password is not exists")
 )
 
+// ErrCredentialPolicyViolation общая причина всех PolicyViolation, чтобы их можно было проверить через errors.Is
+var ErrCredentialPolicyViolation = errors.New("credentials violate policy")
+
+// PolicyViolation логин или пароль не прошел проверку политики. Rule - имя нарушенного правила.
+type PolicyViolation struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (v *PolicyViolation) Error() string {
+	return v.Field + ": " + v.Message
+}
+
+func (v *PolicyViolation) Unwrap() error {
+	return ErrCredentialPolicyViolation
+}
+
+// User errors
+var (
+	ErrUserNotFound   = errors.New("user is not found or deleted already")
+	ErrUserIsDisabled = errors.New("user is disabled")
+	// ErrTooManyLoginAttempts логин или IP клиента временно заблокированы после серии неудачных попыток
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts, try again later")
+)
+
+// Roles errors
+var (
+	ErrRoleIsInvalid = errors.New("role must be one of: user, support, admin")
+	// ErrAccessDenied у пользователя нет роли, которой разрешен запрос
+	ErrAccessDenied = errors.New("access is denied for user role")
+)
+
+// Password errors
+var (
+	ErrPasswordIsIncorrect = errors.New("given password is incorrect")
+	ErrPasswordIsEmpty     = errors.New("password must not be empty")
+	ErrResetTokenIsInvalid = errors.New("password reset token is invalid, expired or used already")
+)
+
+// Two-factor errors
+var (
+	ErrTwoFactorIsNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorIsEnabledAlready = errors.New("two-factor authentication is enabled already")
+	ErrTwoFactorCodeIsInvalid    = errors.New("two-factor code is invalid or used already")
+	ErrTwoFactorCodeIsRequired   = errors.New("two-factor code is required")
+)
+
 // Sessions errors
 var (
 	ErrSessionIsExpired           = errors.New("session is expired")
 	ErrSessionUserCanNotBeDefined = errors.New("user can't be defined by session")
+	ErrRefreshTokenIsInvalid      = errors.New("refresh token is invalid, expired or revoked")
+	ErrRefreshTokenIsReused       = errors.New("refresh token is reused, all tokens of its family are revoked")
 )
 
 // Orders errors
@@ -19,9 +69,33 @@ var (
 	ErrOrderAlreadyUploaded              = errors.New("order is already uploaded by this user")
 	ErrOrderAlreadyUploadedByAnotherUser = errors.New("order is already uploaded by another user")
 	ErrOrderInvalidNumberFormat          = errors.New("invalid order number format")
+	ErrOrderNotFound                     = errors.New("order is not found")
+	ErrOrderIsProcessedAlready           = errors.New("order is processed already")
+)
+
+// Accrual errors
+var (
+	ErrAccrualOrderIsNotRegistered = errors.New("order is not registered in accrual system yet")
+	ErrAccrualIsBusy               = errors.New("accrual system throttles requests, try again later")
+)
+
+// Balance errors
+var (
+	ErrBalanceAdjustmentIsZero   = errors.New("balance adjustment must not be zero")
+	ErrBalanceAdjustmentIsTooLow = errors.New("balance adjustment makes collected points less than withdrawn")
 )
 
 // Withdrawal errors
 var (
-	ErrWithdrawalNotEnoughFund = errors.New("you have not enough fund to withdraw")
+	ErrWithdrawalNotEnoughFund    = errors.New("you have not enough fund to withdraw")
+	ErrWithdrawalInvalidSum       = errors.New("sum to withdraw must be positive")
+	ErrWithdrawalOrderAlreadyUsed = errors.New("order is already paid by withdrawal")
+)
+
+// Migration errors
+var (
+	ErrMigrationIsUnknown        = errors.New("applied migration is not found among embedded migrations")
+	ErrMigrationChecksumMismatch = errors.New("applied migration file is changed, checksum mismatch")
+	ErrMigrationStepsInvalid     = errors.New("number of migrations to roll back must be positive")
+	ErrSchemaIsNotUpToDate       = errors.New("DB schema is not up to date, run migrate up")
 )
@@ -0,0 +1,52 @@
+package accrual
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/pkg/errors"
+)
+
+var _ service.AccrualGetter = (*Adapter)(nil)
+
+// Adapter приводит ответы и ошибки Client к доменным типам, с которыми работает service.AccrualPoller
+type Adapter struct {
+	client Client
+}
+
+func NewAdapter(client Client) *Adapter {
+	if client == nil {
+		panic("missing Client, parameter must not be nil")
+	}
+	return &Adapter{client: client}
+}
+
+func (a Adapter) Get(ctx context.Context, num primit.LuhnNumber) (acc entity.Accrual, err error) {
+	res, err := a.client.Get(ctx, num)
+	switch {
+	case errors.Is(err, ErrOrderNotRegistered):
+		return entity.Accrual{}, errors2.ErrAccrualOrderIsNotRegistered
+	case errors.Is(err, ErrTooManyRequests):
+		return entity.Accrual{}, errors.Wrap(errors2.ErrAccrualIsBusy, err.Error())
+	case err != nil:
+		return entity.Accrual{}, err
+	}
+
+	status, ok := statuses[res.Status]
+	if !ok {
+		return entity.Accrual{}, errors.Wrap(ErrStatusUnknown, fmt.Sprintf("order %s: %q", num, res.Status))
+	}
+	return entity.Accrual{Status: status, Accrual: res.Accrual}, nil
+}
+
+// statuses соответствие статусов системы расчета начислений статусам заказа
+var statuses = map[Status]entity.ProcessingStatus{
+	Registered: entity.Processing,
+	Processing: entity.Processing,
+	Invalid:    entity.Invalid,
+	Processed:  entity.Processed,
+}
@@ -0,0 +1,55 @@
+package accrual_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/infra/accrual"
+	mock_accrual "github.com/UndeadDemidov/ya-pr-diploma/internal/infra/accrual/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var errDummy = errors.New("dummy error")
+
+func TestAdapter_Get(t *testing.T) {
+	tests := []struct {
+		name    string
+		res     accrual.Result
+		err     error
+		want    entity.Accrual
+		wantErr error
+	}{
+		{name: "registered", res: accrual.Result{Status: accrual.Registered}, want: entity.Accrual{Status: entity.Processing}},
+		{name: "processing", res: accrual.Result{Status: accrual.Processing}, want: entity.Accrual{Status: entity.Processing}},
+		{name: "invalid", res: accrual.Result{Status: accrual.Invalid}, want: entity.Accrual{Status: entity.Invalid}},
+		{
+			name: "processed",
+			res:  accrual.Result{Status: accrual.Processed, Accrual: 50050},
+			want: entity.Accrual{Status: entity.Processed, Accrual: 50050},
+		},
+		{name: "unknown status", res: accrual.Result{Status: "UNKNOWN"}, wantErr: accrual.ErrStatusUnknown},
+		{name: "not registered", err: accrual.ErrOrderNotRegistered, wantErr: errors2.ErrAccrualOrderIsNotRegistered},
+		{name: "too many requests", err: &accrual.TooManyRequestsError{}, wantErr: errors2.ErrAccrualIsBusy},
+		{name: "client error", err: errDummy, wantErr: errDummy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			client := mock_accrual.NewMockClient(mockCtrl)
+			client.EXPECT().Get(gomock.Any(), gomock.Any()).Return(tt.res, tt.err)
+
+			got, err := accrual.NewAdapter(client).Get(context.Background(), 12345678903)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
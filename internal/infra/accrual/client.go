@@ -0,0 +1,164 @@
+package accrual
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	_ "github.com/golang/mock/mockgen/model"
+	"github.com/pkg/errors"
+)
+
+//go:generate mockgen -destination=./mocks/mock_client.go . Client
+
+const (
+	ordersPath        = "/api/orders/"
+	requestTimeout    = 10 * time.Second
+	defaultRetryAfter = 60 * time.Second
+	maxBodyToRead     = 1024
+)
+
+var (
+	ErrUnexpectedStatusCode = errors.New("unexpected status code from accrual system")
+	ErrOrderNotRegistered   = errors.New("order is not registered in accrual system")
+	ErrTooManyRequests      = errors.New("too many requests to accrual system")
+	ErrStatusUnknown        = errors.New("unknown accrual status")
+)
+
+// rateLimitRe разбирает тело ответа 429: "No more than N requests per minute allowed"
+var rateLimitRe = regexp.MustCompile(`(?i)no more than (\d+) requests? per minute`)
+
+type Client interface {
+	// Get возвращает результат расчета начислений по заказу.
+	// Если заказ не зарегистрирован в системе расчета - возвращает ErrOrderNotRegistered,
+	// если превышено количество запросов - ошибку, совместимую с ErrTooManyRequests.
+	Get(ctx context.Context, num primit.LuhnNumber) (res Result, err error)
+}
+
+// Result ответ системы расчета начислений
+//
+//	{
+//		"order": "<number>",
+//		"status": "PROCESSED",
+//		"accrual": 500
+//	}
+type Result struct {
+	Order   string          `json:"order"`
+	Status  Status          `json:"status"`
+	Accrual primit.Currency `json:"accrual"`
+}
+
+// TooManyRequestsError ошибка превышения количества запросов к системе расчета начислений
+type TooManyRequestsError struct {
+	RetryAfter time.Duration
+	PerMinute  int
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return fmt.Sprintf("%s: retry after %s", ErrTooManyRequests, e.RetryAfter)
+}
+
+func (e *TooManyRequestsError) Is(target error) bool {
+	return target == ErrTooManyRequests
+}
+
+var _ Client = (*HTTPClient)(nil)
+
+// HTTPClient клиент системы расчета начислений.
+// Ограничение на количество запросов общее для всех горутин, использующих один и тот же клиент.
+type HTTPClient struct {
+	baseURL string
+	client  *http.Client
+	limiter *limiter
+}
+
+func NewHTTPClient(addr string) *HTTPClient {
+	if addr == "" {
+		panic("missing accrual system address, parameter must not be empty")
+	}
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		addr = "http://" + addr
+	}
+	return &HTTPClient{
+		baseURL: strings.TrimRight(addr, "/"),
+		client:  &http.Client{Timeout: requestTimeout},
+		limiter: &limiter{},
+	}
+}
+
+// Get запрашивает информацию о расчете начислений по номеру заказа
+// GET /api/orders/{number}
+func (c *HTTPClient) Get(ctx context.Context, num primit.LuhnNumber) (res Result, err error) {
+	err = c.limiter.Wait(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+ordersPath+num.String(), nil)
+	if err != nil {
+		return Result{}, err
+	}
+	r, err := c.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer r.Body.Close()
+
+	switch r.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		return Result{}, ErrOrderNotRegistered
+	case http.StatusTooManyRequests:
+		tmr := parseTooManyRequests(r)
+		c.limiter.Throttle(tmr.RetryAfter, tmr.PerMinute)
+		return Result{}, tmr
+	default:
+		return Result{}, errors.Wrap(ErrUnexpectedStatusCode, fmt.Sprintf("order %s: %d", num, r.StatusCode))
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&res)
+	if err != nil {
+		return Result{}, err
+	}
+	if !res.Status.IsValid() {
+		return Result{}, errors.Wrap(ErrStatusUnknown, fmt.Sprintf("order %s: %q", num, res.Status))
+	}
+	return res, nil
+}
+
+func parseTooManyRequests(r *http.Response) *TooManyRequestsError {
+	tmr := &TooManyRequestsError{RetryAfter: parseRetryAfter(r.Header.Get("Retry-After"))}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyToRead))
+	if err != nil {
+		return tmr
+	}
+	if m := rateLimitRe.FindSubmatch(body); m != nil {
+		tmr.PerMinute, _ = strconv.Atoi(string(m[1]))
+	}
+	return tmr
+}
+
+// parseRetryAfter разбирает заголовок Retry-After, который может содержать как количество секунд, так и дату
+func parseRetryAfter(val string) time.Duration {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return defaultRetryAfter
+	}
+	if sec, err := strconv.Atoi(val); err == nil && sec >= 0 {
+		return time.Duration(sec) * time.Second
+	}
+	if t, err := http.ParseTime(val); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
@@ -0,0 +1,138 @@
+package accrual
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_Get(t *testing.T) {
+	type response struct {
+		status  int
+		headers map[string]string
+		body    string
+	}
+	tests := []struct {
+		name     string
+		response response
+		want     Result
+		wantErr  bool
+		errIs    error
+	}{
+		{
+			name: "processed order",
+			response: response{
+				status:  http.StatusOK,
+				headers: map[string]string{"Content-Type": "application/json"},
+				body:    `{"order": "12345678903", "status": "PROCESSED", "accrual": 500.5}`,
+			},
+			want: Result{Order: "12345678903", Status: Processed, Accrual: 50050},
+		},
+		{
+			name: "registered order without accrual",
+			response: response{
+				status:  http.StatusOK,
+				headers: map[string]string{"Content-Type": "application/json"},
+				body:    `{"order": "12345678903", "status": "REGISTERED"}`,
+			},
+			want: Result{Order: "12345678903", Status: Registered},
+		},
+		{
+			name: "unknown status",
+			response: response{
+				status: http.StatusOK,
+				body:   `{"order": "12345678903", "status": "DONE"}`,
+			},
+			wantErr: true,
+		},
+		{
+			name:     "order is not registered",
+			response: response{status: http.StatusNoContent},
+			wantErr:  true,
+			errIs:    ErrOrderNotRegistered,
+		},
+		{
+			name: "too many requests",
+			response: response{
+				status:  http.StatusTooManyRequests,
+				headers: map[string]string{"Content-Type": "text/plain", "Retry-After": "0"},
+				body:    "No more than 60 requests per minute allowed",
+			},
+			wantErr: true,
+			errIs:   ErrTooManyRequests,
+		},
+		{
+			name:     "internal server error",
+			response: response{status: http.StatusInternalServerError},
+			wantErr:  true,
+			errIs:    ErrUnexpectedStatusCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/orders/12345678903", r.URL.Path)
+				for k, v := range tt.response.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.response.status)
+				_, _ = w.Write([]byte(tt.response.body))
+			}))
+			defer srv.Close()
+
+			c := NewHTTPClient(srv.URL)
+			got, err := c.Get(context.Background(), primit.LuhnNumber(12345678903))
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.errIs != nil {
+					assert.ErrorIs(t, err, tt.errIs)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHTTPClient_Get_Throttling(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte("No more than 600 requests per minute allowed"))
+	}))
+	defer srv.Close()
+
+	c := NewHTTPClient(srv.URL)
+	_, err := c.Get(context.Background(), primit.LuhnNumber(12345678903))
+	var tmr *TooManyRequestsError
+	require.True(t, errors.As(err, &tmr))
+	assert.Equal(t, 600, tmr.PerMinute)
+	assert.Equal(t, time.Duration(0), tmr.RetryAfter)
+	assert.Equal(t, 100*time.Millisecond, c.limiter.interval)
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		val  string
+		want time.Duration
+	}{
+		{name: "seconds", val: "60", want: 60 * time.Second},
+		{name: "empty", val: "", want: defaultRetryAfter},
+		{name: "garbage", val: "soon", want: defaultRetryAfter},
+		{name: "date in the past", val: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseRetryAfter(tt.val))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package accrual
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter равномерно распределяет запросы во времени.
+// Пока система расчета не ответила 429, ограничений нет.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration // минимальный интервал между запросами
+	next     time.Time     // время, раньше которого нельзя отправлять следующий запрос
+}
+
+// Wait резервирует место для следующего запроса и ждет его наступления
+func (l *limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	d := at.Sub(now)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Throttle приостанавливает запросы на retryAfter и,
+// если известно допустимое количество запросов в минуту, ограничивает частоту запросов
+func (l *limiter) Throttle(retryAfter time.Duration, perMinute int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if perMinute > 0 {
+		l.interval = time.Minute / time.Duration(perMinute)
+	}
+	until := time.Now().Add(retryAfter)
+	if until.After(l.next) {
+		l.next = until
+	}
+}
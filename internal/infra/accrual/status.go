@@ -0,0 +1,44 @@
+package accrual
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Status статус расчета начисления в системе расчета баллов лояльности
+type Status string
+
+var _ json.Unmarshaler = (*Status)(nil)
+
+const (
+	// Registered заказ зарегистрирован, но начисление не рассчитано
+	Registered Status = "REGISTERED"
+	// Invalid заказ не принят к расчету, и вознаграждение не будет начислено
+	Invalid Status = "INVALID"
+	// Processing расчет начисления в процессе
+	Processing Status = "PROCESSING"
+	// Processed расчет начисления окончен
+	Processed Status = "PROCESSED"
+)
+
+func (s Status) IsValid() bool {
+	switch s {
+	case Registered, Invalid, Processing, Processed:
+		return true
+	}
+	return false
+}
+
+// IsFinal возвращает true для статусов, которые больше не изменятся
+func (s Status) IsFinal() bool {
+	return s == Invalid || s == Processed
+}
+
+func (s *Status) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("accrual status must be a string: %w", err)
+	}
+	*s = Status(v)
+	return nil
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	_ auth.Notifier = (*LogNotifier)(nil)
+	_ auth.Notifier = (*FileNotifier)(nil)
+)
+
+// LogNotifier пишет токены сброса пароля в лог. Только для локального запуска!
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n LogNotifier) NotifyPasswordReset(_ context.Context, login, token string, expiresAt time.Time) error {
+	log.Warn().Msgf("password reset token for %s: %s (expires at %s)", login, token, expiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// FileNotifier дописывает токены сброса пароля в файл по одному JSON на строку.
+// Файл можно забирать почтовым роботом или читать при локальной отладке.
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	if path == "" {
+		panic("missing path, parameter must not be empty")
+	}
+	return &FileNotifier{path: path}
+}
+
+type resetMessage struct {
+	Login     string    `json:"login"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (n *FileNotifier) NotifyPasswordReset(_ context.Context, login, token string, expiresAt time.Time) (err error) {
+	msg, err := json.Marshal(resetMessage{Login: login, Token: token, ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	_, err = f.Write(append(msg, '\n'))
+	return err
+}
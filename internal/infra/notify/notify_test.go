@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileNotifier_NotifyPasswordReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resets.jsonl")
+	n := NewFileNotifier(path)
+	expiresAt := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, n.NotifyPasswordReset(context.Background(), "first", "token1", expiresAt))
+	require.NoError(t, n.NotifyPasswordReset(context.Background(), "second", "token2", expiresAt))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var got []resetMessage
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var msg resetMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		got = append(got, msg)
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []resetMessage{
+		{Login: "first", Token: "token1", ExpiresAt: expiresAt},
+		{Login: "second", Token: "token2", ExpiresAt: expiresAt},
+	}, got)
+}
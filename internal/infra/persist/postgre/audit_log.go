@@ -0,0 +1,35 @@
+package postgre
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const insertAuditEntry = `INSERT INTO audit_log (actor_id, by_key, action, target, status, client_ip, request_id, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+// AuditLog журнал действий в административном API. Записи только добавляются
+type AuditLog struct {
+	db *pgxpool.Pool
+}
+
+var _ access.AuditLog = (*AuditLog)(nil)
+
+func NewAuditLog(db *pgxpool.Pool) *AuditLog {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &AuditLog{db: db}
+}
+
+func (al AuditLog) Record(ctx context.Context, entry access.AuditEntry) error {
+	var actorID *string
+	if entry.ActorID != "" {
+		actorID = &entry.ActorID
+	}
+	_, err := conn(ctx, al.db).Exec(ctx, insertAuditEntry, actorID, entry.ByKey, entry.Action, entry.Target,
+		entry.Status, entry.ClientIP, entry.RequestID, entry.At)
+	return err
+}
@@ -6,17 +6,22 @@ import (
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
 	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
-	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
 const (
-	selectUserByLogin    = "SELECT user_id FROM auth WHERE login=$1"
-	selectAuthentication = "SELECT user_id FROM auth WHERE login=$1 AND PASSWORD=$2"
-	insertCredentials    = "INSERT INTO auth (user_id, login, password) VALUES ($1, $2, $3)"
+	selectUserByLogin  = "SELECT user_id FROM auth WHERE login=$1"
+	selectPasswordHash = "SELECT a.user_id, u.status, a.password FROM auth a JOIN users u ON u.id = a.user_id WHERE a.login=$1"
+	selectUserPassword = "SELECT password FROM auth WHERE user_id=$1"
+	selectUserLogin    = "SELECT login FROM auth WHERE user_id=$1"
+	selectUserStatus   = "SELECT status FROM users WHERE id=$1"
+	insertCredentials  = "INSERT INTO auth (user_id, login, password) VALUES ($1, $2, $3)"
+	updatePasswordHash = "UPDATE auth SET password=$2 WHERE user_id=$1"
+	updateUserStatus   = "UPDATE users SET status=$2, status_changed_at=NOW() WHERE id=$1 AND status <> 'DELETED'"
+	deleteCredentials  = "DELETE FROM auth WHERE user_id=$1"
+	deleteResetTokens  = "DELETE FROM password_resets WHERE user_id=$1"
 )
 
 type Auth struct {
@@ -33,10 +38,9 @@ func NewAuth(db *pgxpool.Pool) *Auth {
 }
 
 func (a Auth) Create(ctx context.Context, usr user.User, login, pword string) error {
-	_, err := a.db.Exec(ctx, insertCredentials, usr.ID, login, pword)
+	_, err := conn(ctx, a.db).Exec(ctx, insertCredentials, usr.ID, login, pword)
 	if err != nil {
-		var pgErr pq.Error
-		if errors.As(err, &pgErr); pgErr.Code == pgerrcode.UniqueViolation {
+		if isUniqueViolation(err) {
 			return errors2.ErrLoginIsInUseAlready
 		}
 		return err
@@ -46,21 +50,89 @@ func (a Auth) Create(ctx context.Context, usr user.User, login, pword string) er
 
 func (a Auth) Read(ctx context.Context, login string) (usr user.User, err error) {
 	var userID string
-	err = a.db.QueryRow(ctx, selectUserByLogin, login).Scan(&userID)
+	err = conn(ctx, a.db).QueryRow(ctx, selectUserByLogin, login).Scan(&userID)
 	if err != nil {
 		return user.User{}, err
 	}
 	return user.User{ID: userID}, nil
 }
 
-func (a Auth) ReadWithPassword(ctx context.Context, login, pword string) (usr user.User, err error) {
-	var userID string
-	err = a.db.QueryRow(ctx, selectAuthentication, login, pword).Scan(&userID)
+func (a Auth) ReadPasswordHash(ctx context.Context, login string) (usr user.User, hash string, err error) {
+	var userID, status string
+	err = conn(ctx, a.db).QueryRow(ctx, selectPasswordHash, login).Scan(&userID, &status, &hash)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return user.User{}, errors2.ErrPairLoginPwordIsNotExist
+			return user.User{}, "", errors2.ErrPairLoginPwordIsNotExist
 		}
-		return user.User{}, err
+		return user.User{}, "", err
 	}
-	return user.User{ID: userID}, nil
+	return user.User{ID: userID, Status: user.Status(status)}, hash, nil
+}
+
+func (a Auth) ReadUserPasswordHash(ctx context.Context, usr user.User) (hash string, err error) {
+	err = conn(ctx, a.db).QueryRow(ctx, selectUserPassword, usr.ID).Scan(&hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors2.ErrPairLoginPwordIsNotExist
+		}
+		return "", err
+	}
+	return hash, nil
+}
+
+func (a Auth) ReadLogin(ctx context.Context, usr user.User) (login string, err error) {
+	err = conn(ctx, a.db).QueryRow(ctx, selectUserLogin, usr.ID).Scan(&login)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors2.ErrPairLoginPwordIsNotExist
+		}
+		return "", err
+	}
+	return login, nil
+}
+
+func (a Auth) UpdatePasswordHash(ctx context.Context, usr user.User, hash string) error {
+	tag, err := conn(ctx, a.db).Exec(ctx, updatePasswordHash, usr.ID, hash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors2.ErrPairLoginPwordIsNotExist
+	}
+	return nil
+}
+
+func (a Auth) ReadStatus(ctx context.Context, usr user.User) (status user.Status, err error) {
+	var str string
+	err = conn(ctx, a.db).QueryRow(ctx, selectUserStatus, usr.ID).Scan(&str)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors2.ErrUserNotFound
+		}
+		return "", err
+	}
+	return user.Status(str), nil
+}
+
+func (a Auth) UpdateStatus(ctx context.Context, usr user.User, status user.Status) error {
+	tag, err := conn(ctx, a.db).Exec(ctx, updateUserStatus, usr.ID, string(status))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors2.ErrUserNotFound
+	}
+	return nil
+}
+
+// Delete вместе с кредами удаляет токены сброса пароля и второй фактор
+func (a Auth) Delete(ctx context.Context, usr user.User) error {
+	for _, query := range []string{deleteResetTokens, deleteUserRecoveryCode, deleteTwoFactor} {
+		_, err := conn(ctx, a.db).Exec(ctx, query, usr.ID)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := conn(ctx, a.db).Exec(ctx, deleteCredentials, usr.ID)
+	return err
 }
@@ -0,0 +1,74 @@
+package postgre
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+const (
+	// balances пополняется триггером collect_accrual при переходе заказа в статус PROCESSED
+	selectBalance = "SELECT collected, withdrawn FROM balances WHERE user_id=$1"
+	adjustBalance = `INSERT INTO balances (user_id, collected) VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET collected  = balances.collected + EXCLUDED.collected,
+        updated_at = NOW()
+RETURNING collected, withdrawn`
+)
+
+type Balance struct {
+	db *pgxpool.Pool
+}
+
+var (
+	_ service.BalanceRepository = (*Balance)(nil)
+	_ service.BalanceAdjuster   = (*Balance)(nil)
+)
+
+func NewBalance(db *pgxpool.Pool) *Balance {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &Balance{db: db}
+}
+
+func (b Balance) Read(ctx context.Context, usr user.User) (bal entity.Balance, err error) {
+	var collected, withdrawn int64
+	err = conn(ctx, b.db).QueryRow(ctx, selectBalance, usr.ID).Scan(&collected, &withdrawn)
+	if err != nil {
+		// пока у пользователя не было ни начислений, ни списаний - строки с балансом нет
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Balance{User: usr}, nil
+		}
+		return entity.Balance{}, err
+	}
+	return entity.Balance{
+		User:      usr,
+		Collected: primit.Currency(collected),
+		Withdrawn: primit.Currency(withdrawn),
+	}, nil
+}
+
+// Adjust ограничение balances_current_check не дает начисленным баллам стать меньше списанных
+func (b Balance) Adjust(ctx context.Context, usr user.User, delta primit.Currency) (bal entity.Balance, err error) {
+	var collected, withdrawn int64
+	err = conn(ctx, b.db).QueryRow(ctx, adjustBalance, usr.ID, int64(delta)).Scan(&collected, &withdrawn)
+	if err != nil {
+		if isCheckViolation(err) {
+			return entity.Balance{}, errors2.ErrBalanceAdjustmentIsTooLow
+		}
+		return entity.Balance{}, err
+	}
+	return entity.Balance{
+		User:      usr,
+		Collected: primit.Currency(collected),
+		Withdrawn: primit.Currency(withdrawn),
+	}, nil
+}
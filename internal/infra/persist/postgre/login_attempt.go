@@ -0,0 +1,134 @@
+package postgre
+
+import (
+	"context"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+const (
+	// счетчик создается пустым, чтобы первую попытку тоже можно было посчитать под блокировкой строки
+	insertEmptyLoginAttempt    = "INSERT INTO login_attempts (key) VALUES ($1) ON CONFLICT (key) DO NOTHING"
+	selectLoginAttemptForLock  = "SELECT failures, last_failed_at, locked_until, NOW() FROM login_attempts WHERE key=$1 FOR UPDATE"
+	updateLoginAttempt         = "UPDATE login_attempts SET failures=$2, last_failed_at=$3, locked_until=$4 WHERE key=$1"
+	updateLoginAttemptReleased = "UPDATE login_attempts SET failures=$2, locked_until=$3 WHERE key=$1"
+	deleteLoginAttempts        = "DELETE FROM login_attempts WHERE key=$1"
+	deleteExpiredLoginAttempts = `DELETE FROM login_attempts
+WHERE last_failed_at < NOW() - $1 * INTERVAL '1 second' AND (locked_until IS NULL OR locked_until <= NOW())`
+)
+
+// LoginAttempt хранит счетчики неудачных попыток входа в БД, чтобы блокировка действовала на всех экземплярах сервиса.
+// Ключи хранятся в виде хеша, чтобы в таблице не оседали логины и адреса клиентов.
+type LoginAttempt struct {
+	db     *pgxpool.Pool
+	window time.Duration
+}
+
+var _ access.AttemptStore = (*LoginAttempt)(nil)
+
+// NewLoginAttempt window - время, через которое счетчик неудач сбрасывается
+func NewLoginAttempt(db *pgxpool.Pool, window time.Duration) *LoginAttempt {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &LoginAttempt{db: db, window: window}
+}
+
+// Acquire проверяет и учитывает попытку в одной транзакции с блокировкой строки счетчика,
+// поэтому параллельные попытки по одному ключу учитываются строго по очереди
+func (la LoginAttempt) Acquire(ctx context.Context, key string, lock func(attempts int) time.Duration) (lockedUntil time.Time, err error) {
+	tx, err := conn(ctx, la.db).Begin(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	hash := hashToken(key)
+	_, err = tx.Exec(ctx, insertEmptyLoginAttempt, hash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	failures, lastFail, locked, now, err := selectForLock(ctx, tx, hash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if locked != nil && locked.After(now) {
+		return *locked, tx.Commit(ctx)
+	}
+
+	if now.Sub(lastFail) > la.window {
+		failures = 0
+	}
+	failures++
+	var until *time.Time
+	if delay := lock(failures); delay > 0 {
+		t := now.Add(delay)
+		until = &t
+	}
+	_, err = tx.Exec(ctx, updateLoginAttempt, hash, failures, now, until)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, tx.Commit(ctx)
+}
+
+func (la LoginAttempt) Release(ctx context.Context, key string, lock func(attempts int) time.Duration) (err error) {
+	tx, err := conn(ctx, la.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	hash := hashToken(key)
+	failures, _, locked, _, err := selectForLock(ctx, tx, hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tx.Commit(ctx)
+		}
+		return err
+	}
+	if failures > 0 {
+		failures--
+	}
+	if lock(failures) <= 0 {
+		locked = nil
+	}
+	_, err = tx.Exec(ctx, updateLoginAttemptReleased, hash, failures, locked)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func selectForLock(ctx context.Context, tx pgx.Tx, hash string) (failures int, lastFail time.Time, locked *time.Time, now time.Time, err error) {
+	err = tx.QueryRow(ctx, selectLoginAttemptForLock, hash).Scan(&failures, &lastFail, &locked, &now)
+	return failures, lastFail, locked, now, err
+}
+
+func (la LoginAttempt) Reset(ctx context.Context, key string) error {
+	_, err := conn(ctx, la.db).Exec(ctx, deleteLoginAttempts, hashToken(key))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (la LoginAttempt) DeleteExpired(ctx context.Context) error {
+	_, err := conn(ctx, la.db).Exec(ctx, deleteExpiredLoginAttempts, la.window.Seconds())
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,488 @@
+package postgre
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"sort"
+
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/pgx"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/jackc/pgx/v4/stdlib"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+const (
+	migrationsDir = "migrations"
+	// migrationLockID ключ advisory lock, под которым выполняются проверка, накат и учет контрольных сумм.
+	// Отличается от ключа, который берет сам golang-migrate, иначе он ждал бы собственную блокировку.
+	migrationLockID = 7_246_118_519
+
+	migrationChecksumsTable = "schema_migration_checksums"
+
+	lockMigrations          = "SELECT pg_advisory_lock($1)"
+	unlockMigrations        = "SELECT pg_advisory_unlock($1)"
+	selectTableExists       = "SELECT to_regclass($1) IS NOT NULL"
+	createMigrationChecksum = `CREATE TABLE IF NOT EXISTS schema_migration_checksums
+(
+    version    BIGINT                    NOT NULL
+        CONSTRAINT schema_migration_checksums_pk
+            PRIMARY KEY,
+    name       VARCHAR                   NOT NULL,
+    checksum   VARCHAR                   NOT NULL,
+    applied_at timestamptz DEFAULT NOW() NOT NULL
+)`
+	selectMigrationChecksums = "SELECT version, checksum FROM schema_migration_checksums"
+	// golang-migrate хранит в schema_migrations не больше одной строки, -1 - схема пустая, но помечена dirty
+	selectSchemaVersion     = "SELECT COALESCE(MAX(version), -1), COALESCE(BOOL_OR(dirty), FALSE) FROM schema_migrations"
+	insertMigrationChecksum = `INSERT INTO schema_migration_checksums (version, name, checksum) VALUES ($1, $2, $3)
+ON CONFLICT (version) DO NOTHING`
+	upsertMigrationChecksum = `INSERT INTO schema_migration_checksums (version, name, checksum) VALUES ($1, $2, $3)
+ON CONFLICT (version) DO UPDATE SET name = EXCLUDED.name, checksum = EXCLUDED.checksum, applied_at = NOW()`
+	deleteMigrationChecksums = "DELETE FROM schema_migration_checksums WHERE version > $1"
+	selectLegacyObject       = `SELECT EXISTS(SELECT 1 FROM information_schema.columns
+WHERE table_schema = 'public' AND table_name = $1 AND ($2 = '' OR column_name = $2))`
+)
+
+// legacyObject таблица или столбец, по которому узнается миграция, накатанная прежними версиями сервиса
+type legacyObject struct {
+	table  string
+	column string
+}
+
+// legacyObjects прежние версии сервиса накатывали схему скриптом без учета версий, и в разных установках
+// он успел создать разное число объектов. i-й элемент - объект, который создает миграция i+1.
+var legacyObjects = []legacyObject{
+	{table: "users"},
+	{table: "auth"},
+	{table: "orders"},
+	{table: "balances"},
+	{table: "withdrawals"},
+	{table: "sessions"},
+	{table: "refresh_tokens"},
+	{table: "password_resets"},
+	{table: "users", column: "status"},
+	{table: "login_attempts"},
+	{table: "two_factors"},
+	{table: "users", column: "role"},
+	{table: "audit_log"},
+}
+
+// Migration описание одной встроенной миграции. Checksum - sha256 от up-файла.
+type Migration struct {
+	Version  uint
+	Name     string
+	Checksum string
+	HasDown  bool
+}
+
+// Migrator накатывает встроенные миграции migrations/*.up.sql через golang-migrate.
+// Версия схемы хранится в schema_migrations, контрольные суммы накатанных файлов - в schema_migration_checksums.
+// Все действия выполняются под advisory lock, поэтому одновременно стартующие экземпляры не накатят одну миграцию дважды.
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(db *pgxpool.Pool) (*Migrator, error) {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	migrations, err := LoadMigrations(migrationFS, migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// MigrationState состояние встроенной миграции в БД.
+// Changed - миграция накатана, но ее файл с тех пор изменился.
+type MigrationState struct {
+	Migration
+	Applied bool
+	Changed bool
+}
+
+// SchemaStatus текущая версия схемы и состояние всех встроенных миграций
+type SchemaStatus struct {
+	Version    uint
+	Dirty      bool
+	Migrations []MigrationState
+}
+
+// UpToDate схема накатана до последней встроенной миграции и не требует вмешательства
+func (s SchemaStatus) UpToDate() bool {
+	if s.Dirty {
+		return false
+	}
+	for _, state := range s.Migrations {
+		if !state.Applied || state.Changed {
+			return false
+		}
+	}
+	return true
+}
+
+// Up накатывает все еще не примененные миграции
+func (m Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, false, func(mg *migrate.Migrate) error {
+		err := mg.Up()
+		if errors.Is(err, migrate.ErrNoChange) {
+			return nil
+		}
+		return err
+	})
+}
+
+// Down откатывает n последних миграций
+func (m Migrator) Down(ctx context.Context, n int) error {
+	if n <= 0 {
+		return errors2.ErrMigrationStepsInvalid
+	}
+	return m.run(ctx, false, func(mg *migrate.Migrate) error {
+		return mg.Steps(-n)
+	})
+}
+
+// Force выставляет версию схемы без выполнения миграций и снимает признак dirty.
+// Нужен, чтобы восстановиться после упавшей миграции, поэтому контрольные суммы не сверяет, а перезаписывает.
+// version -1 означает пустую схему.
+func (m Migrator) Force(ctx context.Context, version int) error {
+	if version == 0 || version < -1 || version > int(m.latest()) {
+		return errors.Wrapf(errors2.ErrMigrationIsUnknown, "version %d", version)
+	}
+	return m.run(ctx, true, func(mg *migrate.Migrate) error {
+		return mg.Force(version)
+	})
+}
+
+// Status возвращает версию схемы и состояние встроенных миграций, ничего не накатывая.
+// Только читает: служебные таблицы создаются при первом накате, а до него считаются пустыми.
+// Поэтому работает и под ролью без права создавать таблицы.
+func (m Migrator) Status(ctx context.Context) (status SchemaStatus, err error) {
+	applied := make(map[uint]string)
+	exists, err := m.tableExists(ctx, migrationChecksumsTable)
+	if err != nil {
+		return SchemaStatus{}, err
+	}
+	if exists {
+		applied, err = m.checksums(ctx, m.db)
+		if err != nil {
+			return SchemaStatus{}, err
+		}
+	}
+	exists, err = m.tableExists(ctx, pgx.DefaultMigrationsTable)
+	if err != nil {
+		return SchemaStatus{}, err
+	}
+	if exists {
+		var version int64
+		err = m.db.QueryRow(ctx, selectSchemaVersion).Scan(&version, &status.Dirty)
+		if err != nil {
+			return SchemaStatus{}, err
+		}
+		if version > 0 {
+			status.Version = uint(version)
+		}
+	}
+	for _, mgr := range m.migrations {
+		state := MigrationState{Migration: mgr, Applied: mgr.Version <= status.Version}
+		if checksum, ok := applied[mgr.Version]; ok && state.Applied {
+			state.Changed = checksum != mgr.Checksum
+		}
+		status.Migrations = append(status.Migrations, state)
+	}
+	return status, nil
+}
+
+// CheckUpToDate возвращает ошибку, если схема не накатана до последней встроенной миграции
+func (m Migrator) CheckUpToDate(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if !status.UpToDate() {
+		return errors.Wrapf(errors2.ErrSchemaIsNotUpToDate, "version %d, dirty %v, latest %d", status.Version, status.Dirty, m.latest())
+	}
+	return nil
+}
+
+func (m Migrator) tableExists(ctx context.Context, table string) (exists bool, err error) {
+	err = m.db.QueryRow(ctx, selectTableExists, table).Scan(&exists)
+	return exists, err
+}
+
+func (m Migrator) latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// run берет advisory lock, сверяет контрольные суммы, выполняет action и записывает суммы для новой версии схемы.
+// При force суммы не сверяются и перезаписываются суммами встроенных файлов.
+func (m Migrator) run(ctx context.Context, force bool, action func(mg *migrate.Migrate) error) (err error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, lockMigrations, migrationLockID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// контекст запуска мог быть уже отменен, а блокировку надо снять в любом случае
+		_, errUnlock := conn.Exec(context.Background(), unlockMigrations, migrationLockID)
+		if err == nil {
+			err = errUnlock
+		}
+	}()
+
+	_, err = conn.Exec(ctx, createMigrationChecksum)
+	if err != nil {
+		return err
+	}
+	if !force {
+		err = m.validate(ctx, conn)
+		if err != nil {
+			return err
+		}
+	}
+
+	mg, err := m.newMigrate()
+	if err != nil {
+		return err
+	}
+	defer closeMigrate(mg)
+
+	if !force {
+		err = m.baseline(ctx, mg)
+		if err != nil {
+			return err
+		}
+	}
+	err = action(mg)
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := mg.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		version, err = 0, nil
+	}
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return errors.Errorf("schema is dirty at version %d", version)
+	}
+	err = m.record(ctx, conn, version, force)
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("DB schema is at version %d", version)
+	return nil
+}
+
+func (m Migrator) newMigrate() (*migrate.Migrate, error) {
+	src, err := iofs.New(migrationFS, migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	// golang-migrate не разбирает нативный формат строки подключения постгреса,
+	// поэтому отдаем ему *sql.DB, открытый по уже разобранному конфигу пула
+	db := stdlib.OpenDB(*m.db.Config().ConnConfig)
+	drv, err := pgx.WithInstance(db, &pgx.Config{})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return migrate.NewWithInstance("iofs", src, "pgx", drv)
+}
+
+func closeMigrate(mg *migrate.Migrate) {
+	errSrc, errDB := mg.Close()
+	if errSrc != nil {
+		log.Error().Err(errSrc).Msg("failed to close migration source")
+	}
+	if errDB != nil {
+		log.Error().Err(errDB).Msg("failed to close migration database")
+	}
+}
+
+// baseline помечает схему, созданную прежними версиями сервиса без учета версий, как накатанную до той миграции,
+// объекты которой в ней уже есть. Остальные миграции накатываются как обычно.
+func (m Migrator) baseline(ctx context.Context, mg *migrate.Migrate) error {
+	_, _, err := mg.Version()
+	if !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+	version, err := legacyVersion(func(obj legacyObject) (exists bool, err error) {
+		err = m.db.QueryRow(ctx, selectLegacyObject, obj.table, obj.column).Scan(&exists)
+		return exists, err
+	})
+	if err != nil || version == 0 {
+		return err
+	}
+	log.Warn().Msgf("found DB schema without version, marking it as version %d", version)
+	return mg.Force(int(version))
+}
+
+// legacyVersion последняя миграция, объекты которой и всех предыдущих миграций уже есть в схеме. 0 - схема пустая
+func legacyVersion(exists func(obj legacyObject) (bool, error)) (uint, error) {
+	for i, obj := range legacyObjects {
+		ok, err := exists(obj)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return uint(i), nil
+		}
+	}
+	return uint(len(legacyObjects)), nil
+}
+
+// validate сверяет контрольные суммы накатанных миграций со встроенными файлами
+func (m Migrator) validate(ctx context.Context, conn *pgxpool.Conn) error {
+	applied, err := m.checksums(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return ValidateChecksums(m.migrations, applied)
+}
+
+func (m Migrator) checksums(ctx context.Context, q querier) (map[uint]string, error) {
+	rows, err := q.Query(ctx, selectMigrationChecksums)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[uint]string)
+	for rows.Next() {
+		var (
+			version  uint
+			checksum string
+		)
+		err = rows.Scan(&version, &checksum)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = checksum
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// record записывает суммы миграций до version включительно и удаляет суммы откаченных.
+// При overwrite уже записанные суммы заменяются суммами встроенных файлов.
+func (m Migrator) record(ctx context.Context, conn *pgxpool.Conn, version uint, overwrite bool) error {
+	_, err := conn.Exec(ctx, deleteMigrationChecksums, version)
+	if err != nil {
+		return err
+	}
+	insert := insertMigrationChecksum
+	if overwrite {
+		insert = upsertMigrationChecksum
+	}
+	for _, mgr := range m.migrations {
+		if mgr.Version > version {
+			break
+		}
+		_, err = conn.Exec(ctx, insert, mgr.Version, mgr.Name, mgr.Checksum)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadMigrations читает миграции из каталога dir и возвращает их по возрастанию версии.
+// Каждая up-миграция должна иметь парную down-миграцию.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		parsed, err := source.Parse(entry.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "migration file %s", entry.Name())
+		}
+		mgr, ok := byVersion[parsed.Version]
+		if !ok {
+			mgr = &Migration{Version: parsed.Version, Name: parsed.Identifier}
+			byVersion[parsed.Version] = mgr
+		}
+		if mgr.Name != parsed.Identifier {
+			return nil, errors.Errorf("migration %d has different names: %s and %s", mgr.Version, mgr.Name, parsed.Identifier)
+		}
+		switch parsed.Direction {
+		case source.Up:
+			body, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			mgr.Checksum = Checksum(body)
+		case source.Down:
+			mgr.HasDown = true
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mgr := range byVersion {
+		if mgr.Checksum == "" {
+			return nil, errors.Errorf("migration %d_%s has no up file", mgr.Version, mgr.Name)
+		}
+		if !mgr.HasDown {
+			return nil, errors.Errorf("migration %d_%s has no down file", mgr.Version, mgr.Name)
+		}
+		migrations = append(migrations, *mgr)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// ValidateChecksums проверяет, что каждая накатанная миграция есть среди встроенных и ее файл не менялся
+func ValidateChecksums(migrations []Migration, applied map[uint]string) error {
+	known := make(map[uint]Migration, len(migrations))
+	for _, mgr := range migrations {
+		known[mgr.Version] = mgr
+	}
+	for version, checksum := range applied {
+		mgr, ok := known[version]
+		if !ok {
+			return errors.Wrapf(errors2.ErrMigrationIsUnknown, "version %d", version)
+		}
+		if mgr.Checksum != checksum {
+			return errors.Wrapf(errors2.ErrMigrationChecksumMismatch, "version %d (%s)", version, mgr.Name)
+		}
+	}
+	return nil
+}
+
+// Checksum sha256 содержимого файла миграции в hex
+func Checksum(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
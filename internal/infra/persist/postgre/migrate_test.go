@@ -0,0 +1,176 @@
+package postgre
+
+import (
+	"testing"
+	"testing/fstest"
+
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := LoadMigrations(migrationFS, migrationsDir)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, mgr := range migrations {
+		assert.Equal(t, uint(i+1), mgr.Version, "migrations must be numbered without gaps")
+		assert.True(t, mgr.HasDown, mgr.Name)
+	}
+	assert.GreaterOrEqual(t, len(migrations), len(legacyObjects))
+}
+
+func TestLegacyVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects []legacyObject
+		want    uint
+	}{
+		{name: "empty schema"},
+		{
+			name:    "users and auth only",
+			objects: []legacyObject{{table: "users"}, {table: "auth"}},
+			want:    2,
+		},
+		{
+			name: "gap stops baseline",
+			objects: []legacyObject{
+				{table: "users"}, {table: "auth"}, {table: "orders"}, {table: "balances"}, {table: "withdrawals"},
+				{table: "sessions"}, {table: "refresh_tokens"}, {table: "password_resets"}, {table: "login_attempts"},
+			},
+			want: 8,
+		},
+		{name: "full legacy schema", objects: legacyObjects, want: uint(len(legacyObjects))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := legacyVersion(func(obj legacyObject) (bool, error) {
+				for _, o := range tt.objects {
+					if o == obj {
+						return true, nil
+					}
+				}
+				return false, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "sorted by version",
+			fsys: fstest.MapFS{
+				"m/10_b.up.sql":   {Data: []byte("B")},
+				"m/10_b.down.sql": {Data: []byte("-B")},
+				"m/2_a.up.sql":    {Data: []byte("A")},
+				"m/2_a.down.sql":  {Data: []byte("-A")},
+			},
+			want: []Migration{
+				{Version: 2, Name: "a", Checksum: Checksum([]byte("A")), HasDown: true},
+				{Version: 10, Name: "b", Checksum: Checksum([]byte("B")), HasDown: true},
+			},
+		},
+		{
+			name:    "missing down",
+			fsys:    fstest.MapFS{"m/1_a.up.sql": {Data: []byte("A")}},
+			wantErr: true,
+		},
+		{
+			name:    "missing up",
+			fsys:    fstest.MapFS{"m/1_a.down.sql": {Data: []byte("-A")}},
+			wantErr: true,
+		},
+		{
+			name: "different names",
+			fsys: fstest.MapFS{
+				"m/1_a.up.sql":   {Data: []byte("A")},
+				"m/1_b.down.sql": {Data: []byte("-B")},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "wrong file name",
+			fsys:    fstest.MapFS{"m/a.sql": {Data: []byte("A")}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadMigrations(tt.fsys, "m")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateChecksums(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "a", Checksum: Checksum([]byte("A"))},
+		{Version: 2, Name: "b", Checksum: Checksum([]byte("B"))},
+	}
+	tests := []struct {
+		name    string
+		applied map[uint]string
+		wantErr error
+	}{
+		{
+			name:    "nothing applied",
+			applied: map[uint]string{},
+		},
+		{
+			name:    "applied partially",
+			applied: map[uint]string{1: Checksum([]byte("A"))},
+		},
+		{
+			name:    "file is changed",
+			applied: map[uint]string{1: Checksum([]byte("A")), 2: Checksum([]byte("changed"))},
+			wantErr: errors2.ErrMigrationChecksumMismatch,
+		},
+		{
+			name:    "unknown version",
+			applied: map[uint]string{3: Checksum([]byte("C"))},
+			wantErr: errors2.ErrMigrationIsUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateChecksums(migrations, tt.applied)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSchemaStatus_UpToDate(t *testing.T) {
+	applied := MigrationState{Migration: Migration{Version: 1}, Applied: true}
+	tests := []struct {
+		name   string
+		status SchemaStatus
+		want   bool
+	}{
+		{name: "all applied", status: SchemaStatus{Version: 1, Migrations: []MigrationState{applied}}, want: true},
+		{name: "dirty", status: SchemaStatus{Version: 1, Dirty: true, Migrations: []MigrationState{applied}}},
+		{name: "pending", status: SchemaStatus{Version: 1, Migrations: []MigrationState{applied, {Migration: Migration{Version: 2}}}}},
+		{name: "changed", status: SchemaStatus{Version: 1, Migrations: []MigrationState{{Migration: Migration{Version: 1}, Applied: true, Changed: true}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.status.UpToDate())
+		})
+	}
+}
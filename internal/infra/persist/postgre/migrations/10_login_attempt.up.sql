@@ -0,0 +1,12 @@
+CREATE TABLE login_attempts
+(
+    key            VARCHAR                   NOT NULL
+        CONSTRAINT login_attempts_pk
+            PRIMARY KEY,
+    failures       INTEGER     DEFAULT 0     NOT NULL,
+    last_failed_at timestamptz DEFAULT NOW() NOT NULL,
+    locked_until   timestamptz
+);
+
+CREATE INDEX login_attempts_last_failed_at_index
+    ON login_attempts (last_failed_at);
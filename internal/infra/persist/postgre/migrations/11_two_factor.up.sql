@@ -0,0 +1,23 @@
+CREATE TABLE two_factors
+(
+    user_id    uuid                      NOT NULL
+        CONSTRAINT two_factors_pk
+            PRIMARY KEY
+        CONSTRAINT two_factors_users_id_fk
+            REFERENCES users,
+    secret     VARCHAR                   NOT NULL,
+    last_step  BIGINT      DEFAULT 0     NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    enabled_at timestamptz
+);
+
+CREATE TABLE two_factor_recovery_codes
+(
+    user_id uuid    NOT NULL
+        CONSTRAINT two_factor_recovery_codes_users_id_fk
+            REFERENCES users,
+    code    VARCHAR NOT NULL,
+    used_at timestamptz,
+    CONSTRAINT two_factor_recovery_codes_pk
+        PRIMARY KEY (user_id, code)
+);
@@ -0,0 +1,4 @@
+CREATE TYPE user_role AS ENUM ('USER', 'SUPPORT', 'ADMIN');
+
+ALTER TABLE users
+    ADD COLUMN role user_role DEFAULT 'USER' NOT NULL;
@@ -0,0 +1,17 @@
+CREATE TABLE audit_log
+(
+    id         BIGSERIAL                 NOT NULL
+        CONSTRAINT audit_log_pk
+            PRIMARY KEY,
+    actor_id   uuid,
+    by_key     BOOLEAN     DEFAULT FALSE NOT NULL,
+    action     VARCHAR                   NOT NULL,
+    target     VARCHAR                   NOT NULL,
+    status     INTEGER                   NOT NULL,
+    client_ip  VARCHAR,
+    request_id VARCHAR,
+    created_at timestamptz DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX audit_log_actor_id_created_at_index
+    ON audit_log (actor_id, created_at);
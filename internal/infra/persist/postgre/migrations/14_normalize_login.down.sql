@@ -0,0 +1,4 @@
+-- исходное написание логинов не сохраняется, откатывается только ограничение
+DROP INDEX IF EXISTS auth_login_normalized_uindex;
+
+DROP FUNCTION IF EXISTS normalize_login(VARCHAR);
@@ -0,0 +1,34 @@
+-- normalize_login повторяет auth.CredentialPolicy.NormalizeLogin для логинов, записанных до нормализации.
+-- lower не делает полного свертывания регистра, поэтому ß и ς заменяются отдельно.
+CREATE OR REPLACE FUNCTION normalize_login(login VARCHAR) RETURNS VARCHAR
+    LANGUAGE sql
+    IMMUTABLE
+    STRICT
+    PARALLEL SAFE
+AS
+$$
+SELECT replace(translate(lower(normalize(login, NFKC)), 'ς', 'σ'), 'ß', 'ss')
+$$;
+
+-- логины, которые после нормализации совпадают, нельзя слить автоматически: это разные пользователи
+DO
+$$
+    DECLARE
+        collisions TEXT;
+    BEGIN
+        SELECT string_agg(login, ', ' ORDER BY login)
+        INTO collisions
+        FROM (SELECT normalize_login(login) AS login FROM auth GROUP BY 1 HAVING COUNT(*) > 1) c;
+        IF collisions IS NOT NULL THEN
+            RAISE EXCEPTION 'logins collide after normalization: %', collisions
+                USING HINT = 'rename colliding logins, then force version 13 and migrate up again';
+        END IF;
+    END
+$$;
+
+UPDATE auth
+SET login = normalize_login(login)
+WHERE login <> normalize_login(login);
+
+CREATE UNIQUE INDEX auth_login_normalized_uindex
+    ON auth (normalize_login(login));
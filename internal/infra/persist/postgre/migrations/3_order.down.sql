@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS orders;
+DROP FUNCTION IF EXISTS trigger_set_timestamp;
+DROP TYPE IF EXISTS order_status;
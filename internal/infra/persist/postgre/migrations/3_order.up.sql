@@ -6,7 +6,7 @@ CREATE TABLE orders
         CONSTRAINT orders_pk
             PRIMARY KEY,
     user_id      uuid                                   NOT NULL
-        CONSTRAINT auth_users_id_fk
+        CONSTRAINT orders_users_id_fk
             REFERENCES users,
     number       VARCHAR                                NOT NULL,
     status       order_status DEFAULT 'NEW'             NOT NULL,
@@ -18,6 +18,9 @@ CREATE TABLE orders
 CREATE UNIQUE INDEX orders_number_uindex
     ON orders (number);
 
+CREATE INDEX orders_user_id_uploaded_at_index
+    ON orders (user_id, uploaded_at);
+
 CREATE FUNCTION trigger_set_timestamp()
     RETURNS TRIGGER AS
 $$
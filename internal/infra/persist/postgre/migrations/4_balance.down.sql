@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS collect_accrual ON orders;
+DROP FUNCTION IF EXISTS trigger_collect_accrual;
+DROP TABLE IF EXISTS balances;
@@ -0,0 +1,34 @@
+CREATE TABLE balances
+(
+    user_id    UUID                      NOT NULL
+        CONSTRAINT balances_pk
+            PRIMARY KEY
+        CONSTRAINT balances_users_id_fk
+            REFERENCES users,
+    collected  BIGINT      DEFAULT 0     NOT NULL,
+    withdrawn  BIGINT      DEFAULT 0     NOT NULL,
+    updated_at timestamptz DEFAULT NOW() NOT NULL,
+    CONSTRAINT balances_current_check
+        CHECK (collected >= withdrawn)
+);
+
+CREATE FUNCTION trigger_collect_accrual()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    INSERT INTO balances (user_id, collected)
+    VALUES (NEW.user_id, COALESCE(NEW.accrual, 0))
+    ON CONFLICT (user_id) DO UPDATE
+        SET collected  = balances.collected + EXCLUDED.collected,
+            updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER collect_accrual
+    AFTER
+        UPDATE OF status
+    ON orders
+    FOR EACH ROW
+    WHEN (NEW.status = 'PROCESSED' AND OLD.status <> 'PROCESSED')
+EXECUTE PROCEDURE trigger_collect_accrual();
@@ -0,0 +1,20 @@
+CREATE TABLE withdrawals
+(
+    id           UUID        DEFAULT gen_random_uuid() NOT NULL
+        CONSTRAINT withdrawals_pk
+            PRIMARY KEY,
+    user_id      uuid                                  NOT NULL
+        CONSTRAINT withdrawals_users_id_fk
+            REFERENCES users,
+    number       VARCHAR                               NOT NULL,
+    sum          BIGINT                                NOT NULL
+        CONSTRAINT withdrawals_sum_check
+            CHECK (sum > 0),
+    processed_at timestamptz DEFAULT NOW()             NOT NULL
+);
+
+CREATE UNIQUE INDEX withdrawals_number_uindex
+    ON withdrawals (number);
+
+CREATE INDEX withdrawals_user_id_processed_at_index
+    ON withdrawals (user_id, processed_at);
@@ -0,0 +1,17 @@
+CREATE TABLE sessions
+(
+    token      VARCHAR                   NOT NULL
+        CONSTRAINT sessions_pk
+            PRIMARY KEY,
+    user_id    uuid                      NOT NULL
+        CONSTRAINT sessions_users_id_fk
+            REFERENCES users,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    expires_at timestamptz               NOT NULL
+);
+
+CREATE INDEX sessions_expires_at_index
+    ON sessions (expires_at);
+
+CREATE INDEX sessions_user_id_index
+    ON sessions (user_id);
@@ -0,0 +1,23 @@
+CREATE TABLE refresh_tokens
+(
+    token      VARCHAR                   NOT NULL
+        CONSTRAINT refresh_tokens_pk
+            PRIMARY KEY,
+    family_id  uuid                      NOT NULL,
+    user_id    uuid                      NOT NULL
+        CONSTRAINT refresh_tokens_users_id_fk
+            REFERENCES users,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    expires_at timestamptz               NOT NULL,
+    used_at    timestamptz,
+    revoked_at timestamptz
+);
+
+CREATE INDEX refresh_tokens_family_id_index
+    ON refresh_tokens (family_id);
+
+CREATE INDEX refresh_tokens_user_id_index
+    ON refresh_tokens (user_id);
+
+CREATE INDEX refresh_tokens_expires_at_index
+    ON refresh_tokens (expires_at);
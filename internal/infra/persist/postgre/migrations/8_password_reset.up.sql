@@ -0,0 +1,15 @@
+CREATE TABLE password_resets
+(
+    token      VARCHAR                   NOT NULL
+        CONSTRAINT password_resets_pk
+            PRIMARY KEY,
+    user_id    uuid                      NOT NULL
+        CONSTRAINT password_resets_users_id_fk
+            REFERENCES users,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    expires_at timestamptz               NOT NULL,
+    used_at    timestamptz
+);
+
+CREATE INDEX password_resets_user_id_index
+    ON password_resets (user_id);
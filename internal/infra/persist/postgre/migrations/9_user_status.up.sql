@@ -0,0 +1,5 @@
+CREATE TYPE user_status AS ENUM ('ACTIVE', 'DISABLED', 'DELETED');
+
+ALTER TABLE users
+    ADD COLUMN status            user_status DEFAULT 'ACTIVE' NOT NULL,
+    ADD COLUMN status_changed_at timestamptz;
@@ -0,0 +1,157 @@
+package postgre
+
+import (
+	"context"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+const (
+	insertOrder         = "INSERT INTO orders (user_id, number) VALUES ($1, $2) ON CONFLICT (number) DO NOTHING"
+	selectOrderByNumber = "SELECT id, user_id, number, status, COALESCE(accrual, 0), uploaded_at, processed_at FROM orders WHERE number=$1"
+	selectOrdersByUser  = "SELECT id, user_id, number, status, COALESCE(accrual, 0), uploaded_at, processed_at FROM orders WHERE user_id=$1 ORDER BY uploaded_at"
+	// processed_at обновляется триггером при любом изменении заказа,
+	// поэтому выбранные заказы уходят в конец очереди на проверку
+	claimUnprocessedOrders = `
+UPDATE orders
+SET processed_at = NOW()
+WHERE id IN (SELECT id
+             FROM orders
+             WHERE status IN ('NEW', 'PROCESSING')
+             ORDER BY processed_at
+             LIMIT $1 FOR UPDATE SKIP LOCKED)
+RETURNING id, user_id, number, status, COALESCE(accrual, 0), uploaded_at, processed_at`
+	// заказ в конечном статусе не меняется, даже если его успели обработать параллельно
+	updateOrderAccrual = "UPDATE orders SET status=$2, accrual=$3 WHERE id=$1 AND status NOT IN ('PROCESSED', 'INVALID')"
+	requeueOrder       = `UPDATE orders
+SET status = 'NEW'
+WHERE number = $1 AND status <> 'PROCESSED'
+RETURNING id, user_id, number, status, COALESCE(accrual, 0), uploaded_at, processed_at`
+)
+
+type Order struct {
+	db *pgxpool.Pool
+}
+
+var (
+	_ service.OrderRepository   = (*Order)(nil)
+	_ service.AccrualRepository = (*Order)(nil)
+	_ service.OrderRequeuer     = (*Order)(nil)
+)
+
+func NewOrder(db *pgxpool.Pool) *Order {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &Order{db: db}
+}
+
+func (o Order) Create(ctx context.Context, ord entity.Order) error {
+	tag, err := conn(ctx, o.db).Exec(ctx, insertOrder, ord.User.ID, ord.Number.String())
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors2.ErrOrderAlreadyUploaded
+	}
+	return nil
+}
+
+func (o Order) Read(ctx context.Context, num primit.LuhnNumber) (ord entity.Order, err error) {
+	ord, err = scanOrder(conn(ctx, o.db).QueryRow(ctx, selectOrderByNumber, num.String()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Order{}, errors2.ErrOrderNotFound
+		}
+		return entity.Order{}, err
+	}
+	return ord, nil
+}
+
+func (o Order) List(ctx context.Context, usr user.User) (ords []entity.Order, err error) {
+	rows, err := conn(ctx, o.db).Query(ctx, selectOrdersByUser, usr.ID)
+	if err != nil {
+		return nil, err
+	}
+	return scanOrders(rows)
+}
+
+func (o Order) ListUnprocessed(ctx context.Context, limit int) (ords []entity.Order, err error) {
+	rows, err := conn(ctx, o.db).Query(ctx, claimUnprocessedOrders, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanOrders(rows)
+}
+
+func (o Order) UpdateAccrual(ctx context.Context, ord entity.Order) error {
+	_, err := conn(ctx, o.db).Exec(ctx, updateOrderAccrual, ord.ID, ord.Status.String(), int64(ord.Accrual))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Requeue заказ со статусом PROCESSED не трогает: повторный переход в этот статус снова начислил бы баллы
+func (o Order) Requeue(ctx context.Context, num primit.LuhnNumber) (ord entity.Order, err error) {
+	ord, err = scanOrder(conn(ctx, o.db).QueryRow(ctx, requeueOrder, num.String()))
+	if err == nil {
+		return ord, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return entity.Order{}, err
+	}
+	_, err = o.Read(ctx, num)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	return entity.Order{}, errors2.ErrOrderIsProcessedAlready
+}
+
+func scanOrders(rows pgx.Rows) (ords []entity.Order, err error) {
+	defer rows.Close()
+	for rows.Next() {
+		var ord entity.Order
+		ord, err = scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		ords = append(ords, ord)
+	}
+	return ords, rows.Err()
+}
+
+func scanOrder(row pgx.Row) (ord entity.Order, err error) {
+	var (
+		userID, number, status string
+		accrual                int64
+		uploaded, processed    time.Time
+	)
+	err = row.Scan(&ord.ID, &userID, &number, &status, &accrual, &uploaded, &processed)
+	if err != nil {
+		return entity.Order{}, err
+	}
+
+	ord.User = user.User{ID: userID}
+	num, err := service.ParseLuhnNumber(number)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	ord.Number = num
+	ord.Status, err = entity.ParseProcessingStatus(status)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	ord.Accrual = primit.Currency(accrual)
+	ord.Unloaded = uploaded
+	ord.Processed = processed
+	return ord, nil
+}
@@ -0,0 +1,66 @@
+package postgre
+
+import (
+	"context"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+const (
+	expireUserResetTokens = "UPDATE password_resets SET used_at=NOW() WHERE user_id=$1 AND used_at IS NULL"
+	insertResetToken      = "INSERT INTO password_resets (token, user_id, expires_at) VALUES ($1, $2, $3)"
+	useResetToken         = "UPDATE password_resets SET used_at=NOW() WHERE token=$1 AND used_at IS NULL AND expires_at > NOW() RETURNING user_id"
+)
+
+type PasswordReset struct {
+	db *pgxpool.Pool
+}
+
+var _ auth.ResetTokenRepository = (*PasswordReset)(nil)
+
+func NewPasswordReset(db *pgxpool.Pool) *PasswordReset {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &PasswordReset{db: db}
+}
+
+func (pr PasswordReset) CreateResetToken(ctx context.Context, usr user.User, hash string, expiresAt time.Time) (err error) {
+	tx, err := conn(ctx, pr.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, expireUserResetTokens, usr.ID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, insertResetToken, hash, usr.ID, expiresAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (pr PasswordReset) UseResetToken(ctx context.Context, hash string) (usr user.User, err error) {
+	var userID string
+	err = conn(ctx, pr.db).QueryRow(ctx, useResetToken, hash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.User{}, errors2.ErrResetTokenIsInvalid
+		}
+		return user.User{}, err
+	}
+	return user.User{ID: userID}, nil
+}
@@ -0,0 +1,115 @@
+package postgre
+
+import (
+	"context"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+const (
+	insertRefreshToken = "INSERT INTO refresh_tokens (token, family_id, user_id, expires_at) VALUES ($1, $2, $3, $4)"
+	// погашение атомарно: из двух одновременных обменов одного токена успешен только один
+	useRefreshToken = `UPDATE refresh_tokens SET used_at=NOW()
+WHERE token=$1 AND used_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+RETURNING family_id, user_id`
+	selectRefreshTokenReuse = "SELECT family_id FROM refresh_tokens WHERE token=$1 AND used_at IS NOT NULL AND revoked_at IS NULL"
+	revokeRefreshFamily     = "UPDATE refresh_tokens SET revoked_at=NOW() WHERE family_id=$1 AND revoked_at IS NULL"
+	revokeRefreshByToken    = `UPDATE refresh_tokens SET revoked_at=NOW()
+WHERE family_id=(SELECT family_id FROM refresh_tokens WHERE token=$1) AND revoked_at IS NULL`
+	revokeRefreshByUser  = "UPDATE refresh_tokens SET revoked_at=NOW() WHERE user_id=$1 AND revoked_at IS NULL"
+	deleteExpiredRefresh = "DELETE FROM refresh_tokens WHERE expires_at <= NOW()"
+)
+
+// RefreshToken хранит цепочки refresh-токенов в БД. Как и сессии, токены хранятся в виде хеша.
+type RefreshToken struct {
+	db  *pgxpool.Pool
+	ttl time.Duration
+}
+
+var _ access.RefreshTokenStore = (*RefreshToken)(nil)
+
+func NewRefreshToken(db *pgxpool.Pool, ttl time.Duration) *RefreshToken {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &RefreshToken{db: db, ttl: ttl}
+}
+
+func (rt RefreshToken) Issue(ctx context.Context, userRef access.Referencer) (access.RefreshToken, error) {
+	if userRef == nil {
+		panic("missing access.Referencer, parameter must not be nil")
+	}
+	return rt.add(ctx, access.NewRefreshTokenFamily(), userRef.Reference())
+}
+
+func (rt RefreshToken) Rotate(ctx context.Context, token access.RefreshToken) (string, access.RefreshToken, error) {
+	var family, userID string
+	err := conn(ctx, rt.db).QueryRow(ctx, useRefreshToken, hashToken(string(token))).Scan(&family, &userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", "", rt.checkReuse(ctx, token)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	next, err := rt.add(ctx, family, userID)
+	if err != nil {
+		return "", "", err
+	}
+	return userID, next, nil
+}
+
+// checkReuse если непогашенного токена нет, но есть погашенный - его предъявили повторно.
+// Токен мог быть украден, поэтому отзывается вся цепочка.
+func (rt RefreshToken) checkReuse(ctx context.Context, token access.RefreshToken) error {
+	var family string
+	err := conn(ctx, rt.db).QueryRow(ctx, selectRefreshTokenReuse, hashToken(string(token))).Scan(&family)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors2.ErrRefreshTokenIsInvalid
+	}
+	if err != nil {
+		return err
+	}
+	_, err = conn(ctx, rt.db).Exec(ctx, revokeRefreshFamily, family)
+	if err != nil {
+		return err
+	}
+	return errors2.ErrRefreshTokenIsReused
+}
+
+func (rt RefreshToken) RevokeFamily(ctx context.Context, token access.RefreshToken) error {
+	_, err := conn(ctx, rt.db).Exec(ctx, revokeRefreshByToken, hashToken(string(token)))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rt RefreshToken) RevokeUser(ctx context.Context, userRef string) error {
+	_, err := conn(ctx, rt.db).Exec(ctx, revokeRefreshByUser, userRef)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rt RefreshToken) DeleteExpired(ctx context.Context) error {
+	_, err := conn(ctx, rt.db).Exec(ctx, deleteExpiredRefresh)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rt RefreshToken) add(ctx context.Context, family, userID string) (access.RefreshToken, error) {
+	token := access.NewRefreshToken()
+	_, err := conn(ctx, rt.db).Exec(ctx, insertRefreshToken, hashToken(string(token)), family, userID, time.Now().Add(rt.ttl))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
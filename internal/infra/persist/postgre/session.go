@@ -0,0 +1,99 @@
+package postgre
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+const (
+	insertSession        = "INSERT INTO sessions (token, user_id, expires_at) VALUES ($1, $2, $3)"
+	refreshSession       = "UPDATE sessions SET expires_at=LEAST($2, created_at + $3 * INTERVAL '1 second') WHERE token=$1 AND expires_at > NOW() RETURNING user_id"
+	deleteSession        = "DELETE FROM sessions WHERE token=$1"
+	deleteUserSessions   = "DELETE FROM sessions WHERE user_id=$1"
+	deleteExpiredSession = "DELETE FROM sessions WHERE expires_at <= NOW()"
+)
+
+// Session хранит сессии в БД, поэтому они переживают перезапуск сервиса и доступны всем его экземплярам.
+// Токены хранятся в виде хеша, чтобы утечка таблицы не позволяла подделать сессию.
+type Session struct {
+	db       *pgxpool.Pool
+	idleTTL  time.Duration
+	lifetime time.Duration
+}
+
+var _ access.SessionStore = (*Session)(nil)
+
+// NewSession idleTTL - время, через которое неиспользуемая сессия истекает,
+// lifetime - время, через которое сессия истекает независимо от активности пользователя
+func NewSession(db *pgxpool.Pool, idleTTL, lifetime time.Duration) *Session {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &Session{db: db, idleTTL: idleTTL, lifetime: lifetime}
+}
+
+func (s Session) AddNewSession(ctx context.Context, userRef access.Referencer) (access.SessionToken, error) {
+	if userRef == nil {
+		panic("missing access.Referencer, parameter must not be nil")
+	}
+	token := access.NewSessionToken()
+	expiry := s.idleTTL
+	if s.lifetime < expiry {
+		expiry = s.lifetime
+	}
+	_, err := conn(ctx, s.db).Exec(ctx, insertSession, hashToken(string(token)), userRef.Reference(), time.Now().Add(expiry))
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s Session) GetReference(ctx context.Context, token access.SessionToken) (string, error) {
+	var userID string
+	err := conn(ctx, s.db).QueryRow(ctx, refreshSession, hashToken(string(token)), time.Now().Add(s.idleTTL), s.lifetime.Seconds()).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors2.ErrSessionIsExpired
+		}
+		return "", err
+	}
+	return userID, nil
+}
+
+func (s Session) DeleteSession(ctx context.Context, token access.SessionToken) error {
+	_, err := conn(ctx, s.db).Exec(ctx, deleteSession, hashToken(string(token)))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s Session) DeleteUserSessions(ctx context.Context, userRef string) error {
+	_, err := conn(ctx, s.db).Exec(ctx, deleteUserSessions, userRef)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s Session) DeleteExpired(ctx context.Context) error {
+	_, err := conn(ctx, s.db).Exec(ctx, deleteExpiredSession)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// hashToken в БД хранятся только хеши токенов
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,122 @@
+package postgre
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+)
+
+const (
+	upsertTwoFactorSecret = `INSERT INTO two_factors (user_id, secret) VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET secret=EXCLUDED.secret, created_at=NOW() WHERE two_factors.enabled_at IS NULL`
+	selectTwoFactor        = "SELECT secret, enabled_at IS NOT NULL FROM two_factors WHERE user_id=$1"
+	enableTwoFactor        = "UPDATE two_factors SET enabled_at=NOW(), last_step=$2 WHERE user_id=$1 AND enabled_at IS NULL"
+	useTwoFactorStep       = "UPDATE two_factors SET last_step=$2 WHERE user_id=$1 AND enabled_at IS NOT NULL AND last_step < $2"
+	deleteTwoFactor        = "DELETE FROM two_factors WHERE user_id=$1"
+	insertRecoveryCode     = "INSERT INTO two_factor_recovery_codes (user_id, code) VALUES ($1, $2)"
+	useRecoveryCode        = "UPDATE two_factor_recovery_codes SET used_at=NOW() WHERE user_id=$1 AND code=$2 AND used_at IS NULL"
+	deleteUserRecoveryCode = "DELETE FROM two_factor_recovery_codes WHERE user_id=$1"
+)
+
+// TwoFactor секрет TOTP хранится как есть - без него код не проверить, коды восстановления - только в виде хеша
+type TwoFactor struct {
+	db *pgxpool.Pool
+}
+
+var _ auth.TwoFactorRepository = (*TwoFactor)(nil)
+
+func NewTwoFactor(db *pgxpool.Pool) *TwoFactor {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &TwoFactor{db: db}
+}
+
+func (tf TwoFactor) SaveTwoFactorSecret(ctx context.Context, usr user.User, secret string) error {
+	tag, err := conn(ctx, tf.db).Exec(ctx, upsertTwoFactorSecret, usr.ID, secret)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors2.ErrTwoFactorIsEnabledAlready
+	}
+	return nil
+}
+
+func (tf TwoFactor) ReadTwoFactor(ctx context.Context, usr user.User) (secret string, enabled bool, err error) {
+	err = conn(ctx, tf.db).QueryRow(ctx, selectTwoFactor, usr.ID).Scan(&secret, &enabled)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", false, errors2.ErrTwoFactorIsNotEnabled
+		}
+		return "", false, err
+	}
+	return secret, enabled, nil
+}
+
+func (tf TwoFactor) EnableTwoFactor(ctx context.Context, usr user.User, step int64, recoveryHashes []string) (err error) {
+	tx, err := conn(ctx, tf.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	tag, err := tx.Exec(ctx, enableTwoFactor, usr.ID, step)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors2.ErrTwoFactorIsEnabledAlready
+	}
+	_, err = tx.Exec(ctx, deleteUserRecoveryCode, usr.ID)
+	if err != nil {
+		return err
+	}
+	for _, hash := range recoveryHashes {
+		_, err = tx.Exec(ctx, insertRecoveryCode, usr.ID, hash)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (tf TwoFactor) UseTwoFactorStep(ctx context.Context, usr user.User, step int64) error {
+	tag, err := conn(ctx, tf.db).Exec(ctx, useTwoFactorStep, usr.ID, step)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors2.ErrTwoFactorCodeIsInvalid
+	}
+	return nil
+}
+
+func (tf TwoFactor) UseRecoveryCode(ctx context.Context, usr user.User, hash string) error {
+	tag, err := conn(ctx, tf.db).Exec(ctx, useRecoveryCode, usr.ID, hash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors2.ErrTwoFactorCodeIsInvalid
+	}
+	return nil
+}
+
+func (tf TwoFactor) DeleteTwoFactor(ctx context.Context, usr user.User) error {
+	_, err := conn(ctx, tf.db).Exec(ctx, deleteUserRecoveryCode, usr.ID)
+	if err != nil {
+		return err
+	}
+	_, err = conn(ctx, tf.db).Exec(ctx, deleteTwoFactor, usr.ID)
+	return err
+}
@@ -0,0 +1,62 @@
+package postgre
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/uow"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type txKey struct{}
+
+// querier общий интерфейс пула соединений и транзакции
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// conn возвращает транзакцию, открытую в UnitOfWork.Do, если она есть в контексте, иначе - пул соединений
+func conn(ctx context.Context, db *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+var _ uow.UnitOfWork = (*Transactor)(nil)
+
+type Transactor struct {
+	db *pgxpool.Pool
+}
+
+func NewTransactor(db *pgxpool.Pool) *Transactor {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &Transactor{db: db}
+}
+
+// Do выполняет fn в транзакции. Если транзакция уже открыта, то создается savepoint.
+func (t Transactor) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := conn(ctx, t.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback(ctx)
+			panic(r)
+		}
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+		err = tx.Commit(ctx)
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
@@ -2,20 +2,42 @@ package postgre
 
 import (
 	"context"
+	"time"
 
 	_ "github.com/lib/pq"
 
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
 )
 
-const insertUser = "INSERT INTO users (id) VALUES ($1)"
+const (
+	insertUser = "INSERT INTO users (id) VALUES ($1)"
+	// роль отключенного пользователя не действует, даже пока его access-токен не истек
+	selectUserRole = "SELECT role FROM users WHERE id=$1 AND status = 'ACTIVE'"
+	updateUserRole = "UPDATE users SET role=$2 WHERE id=$1 AND status <> 'DELETED'"
+	// у удаленных пользователей нет кредов, поэтому по логину они не находятся
+	selectUsersByLogin = `
+SELECT u.id, u.status, u.role, a.login, u.created_at
+FROM auth a
+         JOIN users u ON u.id = a.user_id
+WHERE position(lower($1) IN a.login) > 0
+ORDER BY a.login
+LIMIT $2`
+)
 
 type User struct {
 	db *pgxpool.Pool
 }
 
-var _ user.Repository = (*User)(nil)
+var (
+	_ user.Repository       = (*User)(nil)
+	_ service.UserDirectory = (*User)(nil)
+)
 
 func NewUser(db *pgxpool.Pool) *User {
 	if db == nil {
@@ -25,9 +47,56 @@ func NewUser(db *pgxpool.Pool) *User {
 }
 
 func (u User) Create(ctx context.Context, usr user.User) error {
-	_, err := u.db.Exec(ctx, insertUser, usr.ID)
+	_, err := conn(ctx, u.db).Exec(ctx, insertUser, usr.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (u User) ReadRole(ctx context.Context, usr user.User) (user.Role, error) {
+	var role string
+	err := conn(ctx, u.db).QueryRow(ctx, selectUserRole, usr.ID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors2.ErrUserNotFound
+		}
+		return "", err
+	}
+	return user.Role(role), nil
+}
+
+func (u User) UpdateRole(ctx context.Context, usr user.User, role user.Role) error {
+	tag, err := conn(ctx, u.db).Exec(ctx, updateUserRole, usr.ID, string(role))
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return errors2.ErrUserNotFound
+	}
 	return nil
 }
+
+func (u User) FindByLogin(ctx context.Context, login string, limit int) (accs []entity.Account, err error) {
+	rows, err := conn(ctx, u.db).Query(ctx, selectUsersByLogin, login, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id, status, role, lgn string
+			created               time.Time
+		)
+		err = rows.Scan(&id, &status, &role, &lgn, &created)
+		if err != nil {
+			return nil, err
+		}
+		accs = append(accs, entity.Account{
+			User:       user.User{ID: id, Status: user.Status(status), Role: user.Role(role)},
+			Login:      lgn,
+			Registered: created,
+		})
+	}
+	return accs, rows.Err()
+}
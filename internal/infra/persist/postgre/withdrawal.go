@@ -0,0 +1,114 @@
+package postgre
+
+import (
+	"context"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	// строки баланса может еще не быть, если у пользователя не было начислений
+	insertEmptyBalance      = "INSERT INTO balances (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING"
+	selectCurrentForUpdate  = "SELECT collected - withdrawn FROM balances WHERE user_id=$1 FOR UPDATE"
+	insertWithdrawal        = "INSERT INTO withdrawals (user_id, number, sum) VALUES ($1, $2, $3)"
+	updateBalanceWithdrawn  = "UPDATE balances SET withdrawn = withdrawn + $2, updated_at = NOW() WHERE user_id=$1"
+	selectWithdrawalsByUser = "SELECT id, user_id, number, sum, processed_at FROM withdrawals WHERE user_id=$1 ORDER BY processed_at"
+)
+
+type Withdrawal struct {
+	db *pgxpool.Pool
+}
+
+var _ service.WithdrawalRepository = (*Withdrawal)(nil)
+
+func NewWithdrawal(db *pgxpool.Pool) *Withdrawal {
+	if db == nil {
+		panic("missing *pgxpool.Pool, parameter must not be nil")
+	}
+	return &Withdrawal{db: db}
+}
+
+// Create списывает баллы в одной транзакции с блокировкой строки баланса пользователя,
+// поэтому параллельные списания выполняются строго по очереди и не могут увести баланс в минус
+func (w Withdrawal) Create(ctx context.Context, wd entity.Withdrawal) (err error) {
+	tx, err := conn(ctx, w.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, insertEmptyBalance, wd.User.ID)
+	if err != nil {
+		return err
+	}
+	var current int64
+	err = tx.QueryRow(ctx, selectCurrentForUpdate, wd.User.ID).Scan(&current)
+	if err != nil {
+		return err
+	}
+	if primit.Currency(current) < wd.Sum {
+		return errors2.ErrWithdrawalNotEnoughFund
+	}
+
+	_, err = tx.Exec(ctx, insertWithdrawal, wd.User.ID, wd.Order.Number.String(), int64(wd.Sum))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return errors2.ErrWithdrawalOrderAlreadyUsed
+		}
+		return err
+	}
+	_, err = tx.Exec(ctx, updateBalanceWithdrawn, wd.User.ID, int64(wd.Sum))
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (w Withdrawal) List(ctx context.Context, usr user.User) (wtdrwls []entity.Withdrawal, err error) {
+	rows, err := conn(ctx, w.db).Query(ctx, selectWithdrawalsByUser, usr.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var wd entity.Withdrawal
+		wd, err = scanWithdrawal(rows)
+		if err != nil {
+			return nil, err
+		}
+		wtdrwls = append(wtdrwls, wd)
+	}
+	return wtdrwls, rows.Err()
+}
+
+func scanWithdrawal(row pgx.Row) (wd entity.Withdrawal, err error) {
+	var (
+		userID, number string
+		sum            int64
+		processed      time.Time
+	)
+	err = row.Scan(&wd.ID, &userID, &number, &sum, &processed)
+	if err != nil {
+		return entity.Withdrawal{}, err
+	}
+	wd.User = user.User{ID: userID}
+	wd.Order.Number, err = service.ParseLuhnNumber(number)
+	if err != nil {
+		return entity.Withdrawal{}, err
+	}
+	wd.Sum = primit.Currency(sum)
+	wd.Processed = processed
+	return wd, nil
+}
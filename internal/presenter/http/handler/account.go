@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+)
+
+type Account struct {
+	accounts app.AccountManager
+	granters []middleware.Granter
+}
+
+// NewAccount granters нужны, чтобы после удаления закрыть все сессии пользователя
+func NewAccount(accounts app.AccountManager, granters ...middleware.Granter) *Account {
+	if accounts == nil {
+		panic("missing app.AccountManager, parameter must not be nil")
+	}
+	if len(granters) == 0 {
+		panic("missing middleware.Granter, at least one must be set")
+	}
+	return &Account{accounts: accounts, granters: granters}
+}
+
+// Delete
+// DELETE /api/user
+// 200 — учетная запись удалена, все сессии пользователя закрыты;
+// 400 — неверный формат запроса;
+// 403 — неверный пароль;
+// 500 — внутренняя ошибка сервера.
+func (a Account) Delete(w http.ResponseWriter, r *http.Request) {
+	var req deleteAccountRequest
+	err := readJSON(r, &req)
+	if err != nil {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	usr := GetUserFromContext(r.Context())
+	if usr.ID == "" {
+		utils.InternalServerError(w, errors2.ErrSessionUserCanNotBeDefined)
+		return
+	}
+
+	err = a.accounts.DeleteAccount(r.Context(), usr, req.Password)
+	if errors.Is(err, errors2.ErrPasswordIsIncorrect) {
+		utils.ServerError(w, err, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+
+	for _, g := range a.granters {
+		err = g.RevokeAll(w, r, usr)
+		if err != nil {
+			utils.InternalServerError(w, err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+//	{
+//		"password": "<password>"
+//	}
+type deleteAccountRequest struct {
+	Password string `json:"password"`
+}
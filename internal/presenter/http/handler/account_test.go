@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mock "github.com/UndeadDemidov/ya-pr-diploma/internal/app/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	midware "github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccount_Delete(t *testing.T) {
+	type args struct {
+		contentType string
+		request     string
+	}
+	tests := []struct {
+		name    string
+		prepare func(accounts *mock.MockAccountManager)
+		args    args
+		want    int
+	}{
+		{
+			name:    "invalid content type",
+			prepare: func(accounts *mock.MockAccountManager) {},
+			args:    args{contentType: "xxx", request: `{"password": "test"}`},
+			want:    http.StatusBadRequest,
+		},
+		{
+			name: "wrong password",
+			prepare: func(accounts *mock.MockAccountManager) {
+				accounts.EXPECT().DeleteAccount(gomock.Any(), user.User{ID: "1"}, "test").Return(errors2.ErrPasswordIsIncorrect)
+			},
+			args: args{contentType: utils.ContentTypeJSON, request: `{"password": "test"}`},
+			want: http.StatusForbidden,
+		},
+		{
+			name: "internal error",
+			prepare: func(accounts *mock.MockAccountManager) {
+				accounts.EXPECT().DeleteAccount(gomock.Any(), user.User{ID: "1"}, "test").Return(errDummy)
+			},
+			args: args{contentType: utils.ContentTypeJSON, request: `{"password": "test"}`},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "account is deleted",
+			prepare: func(accounts *mock.MockAccountManager) {
+				accounts.EXPECT().DeleteAccount(gomock.Any(), user.User{ID: "1"}, "test").Return(nil)
+			},
+			args: args{contentType: utils.ContentTypeJSON, request: `{"password": "test"}`},
+			want: http.StatusOK,
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			accounts := mock.NewMockAccountManager(mockCtrl)
+			tt.prepare(accounts)
+
+			granter := midware.NewCookieGranter(midware.NewDefaultSessions(), midware.DefaultCookieOptions())
+			request := httptest.NewRequest(http.MethodDelete, "/", strings.NewReader(tt.args.request))
+			request.Header.Set(utils.ContentTypeKey, tt.args.contentType)
+			request = request.WithContext(context.WithValue(request.Context(), midware.ContextUserIDKey, "1"))
+			w := httptest.NewRecorder()
+
+			NewAccount(accounts, granter).Delete(w, request)
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const UserIDParam = "id"
+
+var (
+	ErrUserIDIsInvalid = errors.New("user id must be uuid")
+	ErrOwnRoleChange   = errors.New("admin can't change own role")
+	ErrOwnAccountLock  = errors.New("admin can't disable or delete own account")
+)
+
+type Admin struct {
+	accounts app.AccountManager
+	roles    app.RoleManager
+	revoker  *middleware.AccessRevoker
+}
+
+func NewAdmin(accounts app.AccountManager, roles app.RoleManager, revoker *middleware.AccessRevoker) *Admin {
+	if accounts == nil {
+		panic("missing app.AccountManager, parameter must not be nil")
+	}
+	if roles == nil {
+		panic("missing app.RoleManager, parameter must not be nil")
+	}
+	if revoker == nil {
+		panic("missing *middleware.AccessRevoker, parameter must not be nil")
+	}
+	return &Admin{accounts: accounts, roles: roles, revoker: revoker}
+}
+
+// DisableUser
+// POST /api/admin/users/{id}/disable
+// 200 — вход пользователю запрещен, его сессии закрыты;
+// 400 — неверный формат id, попытка отключить самого себя;
+// 404 — пользователь не найден или удален;
+// 500 — внутренняя ошибка сервера.
+func (a Admin) DisableUser(w http.ResponseWriter, r *http.Request) {
+	if isSelf(w, r, ErrOwnAccountLock) {
+		return
+	}
+	a.changeUser(w, r, a.accounts.DisableUser, true)
+}
+
+// EnableUser
+// POST /api/admin/users/{id}/enable
+// 200 — вход пользователю снова разрешен;
+// 400 — неверный формат id;
+// 404 — пользователь не найден или удален;
+// 500 — внутренняя ошибка сервера.
+func (a Admin) EnableUser(w http.ResponseWriter, r *http.Request) {
+	a.changeUser(w, r, a.accounts.EnableUser, false)
+}
+
+// DeleteUser
+// DELETE /api/admin/users/{id}
+// 200 — креды пользователя удалены, его сессии закрыты, история заказов и списаний сохранена;
+// 400 — неверный формат id, попытка удалить самого себя;
+// 404 — пользователь не найден или удален;
+// 500 — внутренняя ошибка сервера.
+func (a Admin) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if isSelf(w, r, ErrOwnAccountLock) {
+		return
+	}
+	a.changeUser(w, r, a.accounts.DeleteUser, true)
+}
+
+// ChangeRole
+// PUT /api/admin/users/{id}/role
+// 200 — роль изменена, действует со следующего запроса пользователя;
+// 400 — неверный формат id или запроса, неизвестная роль, попытка сменить роль самому себе;
+// 404 — пользователь не найден или удален;
+// 500 — внутренняя ошибка сервера.
+func (a Admin) ChangeRole(w http.ResponseWriter, r *http.Request) {
+	var req changeRoleRequest
+	err := readJSON(r, &req)
+	if err != nil {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+	role, err := user.ParseRole(req.Role)
+	if err != nil {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+	if isSelf(w, r, ErrOwnRoleChange) {
+		return
+	}
+
+	a.changeUser(w, r, func(ctx context.Context, usr user.User) error {
+		return a.roles.ChangeRole(ctx, usr, role)
+	}, false)
+}
+
+func (a Admin) changeUser(w http.ResponseWriter, r *http.Request,
+	change func(ctx context.Context, usr user.User) error, revoke bool) {
+	usr, ok := userFromURL(w, r)
+	if !ok {
+		return
+	}
+
+	err := change(r.Context(), usr)
+	if errors.Is(err, errors2.ErrUserNotFound) {
+		utils.ServerError(w, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+
+	if revoke {
+		err = a.revoker.RevokeUser(r.Context(), usr)
+		if err != nil {
+			utils.InternalServerError(w, err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+//	{
+//		"role": "support"
+//	}
+type changeRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// isSelf администратор не может менять свою учетную запись, иначе последний администратор может
+// случайно лишить себя доступа. Если цель запроса - сам пользователь, то отвечает 400 с ошибкой err и возвращает true
+func isSelf(w http.ResponseWriter, r *http.Request, err error) bool {
+	// id сравнивается в каноническом виде, иначе его можно было бы обойти, записав id заглавными буквами
+	id, parseErr := uuid.Parse(chi.URLParam(r, UserIDParam))
+	if parseErr != nil || id.String() != GetUserFromContext(r.Context()).ID {
+		return false
+	}
+	utils.ServerError(w, err, http.StatusBadRequest)
+	return true
+}
+
+// userFromURL пользователь из параметра маршрута {id}. Если id не uuid - сам отвечает 400 и возвращает false
+func userFromURL(w http.ResponseWriter, r *http.Request) (user.User, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, UserIDParam))
+	if err != nil {
+		utils.ServerError(w, ErrUserIDIsInvalid, http.StatusBadRequest)
+		return user.User{}, false
+	}
+	return user.User{ID: id.String()}, true
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mock "github.com/UndeadDemidov/ya-pr-diploma/internal/app/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	midware "github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testUserID = "6f1c2f5e-3c1b-4a43-9a4b-0c1f5f3b2a10"
+
+func TestAdmin_DisableUser(t *testing.T) {
+	usr := user.User{ID: testUserID}
+	tests := []struct {
+		name       string
+		id         string
+		caller     string
+		prepare    func(accounts *mock.MockAccountManager)
+		want       int
+		wantRevoke bool
+	}{
+		{
+			name:    "invalid id",
+			id:      "1",
+			prepare: func(accounts *mock.MockAccountManager) {},
+			want:    http.StatusBadRequest,
+		},
+		{
+			name:    "own account",
+			id:      testUserID,
+			caller:  testUserID,
+			prepare: func(accounts *mock.MockAccountManager) {},
+			want:    http.StatusBadRequest,
+		},
+		{
+			name:    "own account in upper case",
+			id:      strings.ToUpper(testUserID),
+			caller:  testUserID,
+			prepare: func(accounts *mock.MockAccountManager) {},
+			want:    http.StatusBadRequest,
+		},
+		{
+			name: "user is not found",
+			id:   testUserID,
+			prepare: func(accounts *mock.MockAccountManager) {
+				accounts.EXPECT().DisableUser(gomock.Any(), usr).Return(errors2.ErrUserNotFound)
+			},
+			want: http.StatusNotFound,
+		},
+		{
+			name: "internal error",
+			id:   testUserID,
+			prepare: func(accounts *mock.MockAccountManager) {
+				accounts.EXPECT().DisableUser(gomock.Any(), usr).Return(errDummy)
+			},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "user is disabled",
+			id:   testUserID,
+			prepare: func(accounts *mock.MockAccountManager) {
+				accounts.EXPECT().DisableUser(gomock.Any(), usr).Return(nil)
+			},
+			want:       http.StatusOK,
+			wantRevoke: true,
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			accounts := mock.NewMockAccountManager(mockCtrl)
+			tt.prepare(accounts)
+
+			sessions := midware.NewDefaultSessions()
+			token, err := sessions.AddNewSession(context.Background(), usr)
+			require.NoError(t, err)
+
+			router := chi.NewRouter()
+			router.Post("/api/admin/users/{id}/disable",
+				NewAdmin(accounts, mock.NewMockRoleManager(mockCtrl), midware.NewAccessRevoker(sessions, midware.NewRefreshTokens(midware.DefaultRefreshTokenTTL))).DisableUser)
+			request := httptest.NewRequest(http.MethodPost, "/api/admin/users/"+tt.id+"/disable", nil)
+			if tt.caller != "" {
+				request = request.WithContext(context.WithValue(request.Context(), midware.ContextUserIDKey, tt.caller))
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, request)
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+
+			_, err = sessions.GetReference(context.Background(), token)
+			if tt.wantRevoke {
+				assert.ErrorIs(t, err, errors2.ErrSessionIsExpired)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAdmin_DeleteUser_Own(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	router := chi.NewRouter()
+	router.Delete("/api/admin/users/{id}", NewAdmin(mock.NewMockAccountManager(mockCtrl), mock.NewMockRoleManager(mockCtrl),
+		midware.NewAccessRevoker(midware.NewDefaultSessions(), midware.NewRefreshTokens(midware.DefaultRefreshTokenTTL))).DeleteUser)
+	request := httptest.NewRequest(http.MethodDelete, "/api/admin/users/"+testUserID, nil)
+	request = request.WithContext(context.WithValue(request.Context(), midware.ContextUserIDKey, testUserID))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, request)
+	result := w.Result()
+	defer result.Body.Close()
+	require.Equal(t, http.StatusBadRequest, result.StatusCode)
+}
+
+func TestAdmin_ChangeRole(t *testing.T) {
+	usr := user.User{ID: testUserID}
+	tests := []struct {
+		name    string
+		id      string
+		caller  string
+		request string
+		prepare func(roles *mock.MockRoleManager)
+		want    int
+	}{
+		{
+			name:    "invalid json",
+			id:      testUserID,
+			request: `{"role": `,
+			want:    http.StatusBadRequest,
+		},
+		{
+			name:    "unknown role",
+			id:      testUserID,
+			request: `{"role": "root"}`,
+			want:    http.StatusBadRequest,
+		},
+		{
+			name:    "own role",
+			id:      testUserID,
+			caller:  testUserID,
+			request: `{"role": "user"}`,
+			want:    http.StatusBadRequest,
+		},
+		{
+			name:    "user is not found",
+			id:      testUserID,
+			request: `{"role": "support"}`,
+			prepare: func(roles *mock.MockRoleManager) {
+				roles.EXPECT().ChangeRole(gomock.Any(), usr, user.RoleSupport).Return(errors2.ErrUserNotFound)
+			},
+			want: http.StatusNotFound,
+		},
+		{
+			name:    "role is changed",
+			id:      testUserID,
+			request: `{"role": "Admin"}`,
+			prepare: func(roles *mock.MockRoleManager) {
+				roles.EXPECT().ChangeRole(gomock.Any(), usr, user.RoleAdmin).Return(nil)
+			},
+			want: http.StatusOK,
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			roles := mock.NewMockRoleManager(mockCtrl)
+			if tt.prepare != nil {
+				tt.prepare(roles)
+			}
+
+			router := chi.NewRouter()
+			router.Put("/api/admin/users/{id}/role", NewAdmin(mock.NewMockAccountManager(mockCtrl), roles,
+				midware.NewAccessRevoker(midware.NewDefaultSessions(), midware.NewRefreshTokens(midware.DefaultRefreshTokenTTL))).ChangeRole)
+			request := httptest.NewRequest(http.MethodPut, "/api/admin/users/"+tt.id+"/role", strings.NewReader(tt.request))
+			request.Header.Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+			if tt.caller != "" {
+				request = request.WithContext(context.WithValue(request.Context(), midware.ContextUserIDKey, tt.caller))
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, request)
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+		})
+	}
+}
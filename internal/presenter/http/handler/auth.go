@@ -6,37 +6,58 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
 	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/rs/zerolog/log"
 )
 
 var (
 	// ToDo - заменить на нормальные ответы в json!
 	ErrInvalidContentType   = fmt.Errorf("set header value %v to %v", utils.ContentTypeKey, utils.ContentTypeJSON)
 	ErrProperJSONIsExpected = errors.New("proper JSON is expected, read task description carefully")
+	ErrChallengeIsInvalid   = errors.New("two-factor challenge is invalid or expired, log in again")
 )
 
 type Auth struct {
-	auth     app.Authenticator
-	sessions *middleware.Sessions
+	auth       app.Authenticator
+	twoFactor  app.TwoFactorManager
+	challenges *middleware.Tokens
+	throttle   *middleware.Throttle
+	granters   []middleware.Granter
 }
 
-func NewAuth(auth app.Authenticator, sessions *middleware.Sessions) *Auth {
+// NewAuth challenges - короткоживущие токены между паролем и вторым фактором для пользователей с 2FA,
+// throttle ограничивает подбор паролей и кодов при входе,
+// granters определяют, что получит пользователь после входа - сессионную куку, токен или и то, и другое
+func NewAuth(auth app.Authenticator, twoFactor app.TwoFactorManager, challenges *middleware.Tokens,
+	throttle *middleware.Throttle, granters ...middleware.Granter) *Auth {
 	if auth == nil {
 		panic("missing app.Authenticator, parameter must not be nil")
 	}
-	if sessions == nil {
-		panic("missing *middleware.Sessions, parameter must not be nil")
+	if twoFactor == nil {
+		panic("missing app.TwoFactorManager, parameter must not be nil")
 	}
-	return &Auth{auth: auth, sessions: sessions}
+	if challenges == nil {
+		panic("missing *middleware.Tokens, parameter must not be nil")
+	}
+	if throttle == nil {
+		panic("missing *middleware.Throttle, parameter must not be nil")
+	}
+	if len(granters) == 0 {
+		panic("missing middleware.Granter, at least one must be set")
+	}
+	return &Auth{auth: auth, twoFactor: twoFactor, challenges: challenges, throttle: throttle, granters: granters}
 }
 
 // RegisterUser
 // POST /api/user/register
+// После успешной регистрации пользователь сразу аутентифицируется.
+// Если логин или пароль не проходят политику - 400 с именем нарушенного правила в JSON
 func (a *Auth) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	req := authRequest{}
 	err := req.Read(r)
@@ -45,20 +66,25 @@ func (a *Auth) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = a.auth.SignIn(r.Context(), req.Login, req.Password)
+	usr, err := a.auth.SignIn(r.Context(), req.Login, req.Password)
 	if err != nil {
 		if errors.Is(err, errors2.ErrLoginIsInUseAlready) {
 			utils.ServerError(w, errors2.ErrLoginIsInUseAlready, http.StatusConflict)
 			return
 		}
+		if writePolicyViolation(w, err) {
+			return
+		}
 		utils.InternalServerError(w, err)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
+	a.startSession(w, r, usr)
 }
 
 // LoginUser
 // POST /api/user/login
+// После серии неудачных попыток логин и IP клиента блокируются: 429 с заголовком Retry-After.
+// Если у пользователя включена 2FA, то доступ не выдается: 202 с токеном для второго шага в JSON
 func (a *Auth) LoginUser(w http.ResponseWriter, r *http.Request) {
 	req := authRequest{}
 	err := req.Read(r)
@@ -67,19 +93,189 @@ func (a *Auth) LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// счетчик попыток ведется по тому же логину, по которому ищется пользователь, иначе Bob и bob
+	// получили бы отдельные лимиты
+	req.Login = a.auth.NormalizeLogin(req.Login)
+	wait, err := a.throttle.Acquire(r.Context(), req.Login, r)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	if wait > 0 {
+		middleware.SetRetryAfter(w, wait)
+		utils.ServerError(w, errors2.ErrTooManyLoginAttempts, http.StatusTooManyRequests)
+		return
+	}
+
 	usr, err := a.auth.Login(r.Context(), req.Login, req.Password)
+	if errors.Is(err, errors2.ErrPairLoginPwordIsNotExist) {
+		// попытка уже учтена в Acquire
+		utils.ServerError(w, errors2.ErrPairLoginPwordIsNotExist, http.StatusUnauthorized)
+		return
+	}
 	if err != nil {
-		if errors.Is(err, errors2.ErrPairLoginPwordIsNotExist) {
-			utils.ServerError(w, errors2.ErrPairLoginPwordIsNotExist, http.StatusUnauthorized)
+		releaseErr := a.throttle.Release(r.Context(), req.Login, r)
+		if releaseErr != nil {
+			utils.InternalServerError(w, releaseErr)
+			return
+		}
+		if errors.Is(err, errors2.ErrUserIsDisabled) {
+			utils.ServerError(w, err, http.StatusForbidden)
 			return
 		}
 		utils.InternalServerError(w, err)
 		return
 	}
-	// Можно было и JWT поюзать, но решил для практики поизобретать велосипеды в отпуске,
-	// чтобы не обнулиться в дно за месяц академа
-	cookie := middleware.NewSessionSignedCookie(a.sessions.AddNewSession(usr))
-	cookie.Set(w)
+	err = a.throttle.Succeed(r.Context(), req.Login, r)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+
+	enabled, err := a.twoFactor.IsTwoFactorEnabled(r.Context(), usr)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	if enabled {
+		a.challenge(w, usr)
+		return
+	}
+	a.startSession(w, r, usr)
+}
+
+// LoginTwoFactor
+// POST /api/user/login/2fa
+// 200 — код принят, пользователь аутентифицирован;
+// 400 — неверный формат запроса;
+// 401 — токен второго шага недействителен или истек, неверный код;
+// 403 — пользователь отключен, пока вводил код;
+// 429 — слишком много неверных кодов, в заголовке Retry-After - сколько ждать;
+// 500 — внутренняя ошибка сервера.
+func (a *Auth) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req loginTwoFactorRequest
+	err := readJSON(r, &req)
+	if err != nil {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	ref, err := a.challenges.Parse(req.Challenge)
+	if err != nil {
+		utils.ServerError(w, ErrChallengeIsInvalid, http.StatusUnauthorized)
+		return
+	}
+	usr := user.User{ID: ref}
+	if !checkTwoFactorCode(w, r, a.throttle, usr, req.Code, a.twoFactor.VerifyTwoFactor, http.StatusUnauthorized) {
+		return
+	}
+	// пока пользователь вводил код, его могли отключить
+	err = a.auth.CheckActive(r.Context(), usr)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors2.ErrUserIsDisabled):
+			utils.ServerError(w, err, http.StatusForbidden)
+		case errors.Is(err, errors2.ErrUserNotFound):
+			utils.ServerError(w, ErrChallengeIsInvalid, http.StatusUnauthorized)
+		default:
+			utils.InternalServerError(w, err)
+		}
+		return
+	}
+	a.startSession(w, r, usr)
+}
+
+func (a *Auth) challenge(w http.ResponseWriter, usr user.User) {
+	token, expiresAt, err := a.challenges.Issue(usr)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	w.Header().Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+	w.WriteHeader(http.StatusAccepted)
+	err = json.NewEncoder(w).Encode(challengeResponse{Challenge: token, ExpiresAt: expiresAt})
+	if err != nil {
+		log.Error().Err(err).Msg("can't write two-factor challenge")
+	}
+}
+
+// startSession выдает пользователю доступ: сессию в подписанной куке и/или токены в JSON
+func (a *Auth) startSession(w http.ResponseWriter, r *http.Request, usr user.User) {
+	var body interface{}
+	for _, g := range a.granters {
+		granted, err := g.Grant(w, r, usr)
+		if err != nil {
+			utils.InternalServerError(w, err)
+			return
+		}
+		if granted != nil {
+			body = granted
+		}
+	}
+	writeGranted(w, body)
+}
+
+// writeGranted body - тело ответа от Granter, nil - доступ выдан только в куке
+func writeGranted(w http.ResponseWriter, body interface{}) {
+	if body == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	writeJSON(w, body)
+}
+
+// RefreshToken
+// POST /api/user/token/refresh
+// Обменивает refresh-токен из заголовка X-Refresh-Token на новую пару токенов в JSON
+func (a *Auth) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	for _, g := range a.granters {
+		refresher, ok := g.(middleware.Refresher)
+		if !ok {
+			continue
+		}
+		body, err := refresher.Refresh(w, r)
+		if err != nil {
+			if errors.Is(err, middleware.ErrRefreshTokenNotSet) ||
+				errors.Is(err, errors2.ErrRefreshTokenIsInvalid) ||
+				errors.Is(err, errors2.ErrRefreshTokenIsReused) {
+				utils.ServerError(w, err, http.StatusUnauthorized)
+				return
+			}
+			utils.InternalServerError(w, err)
+			return
+		}
+		writeGranted(w, body)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// Logout
+// POST /api/user/logout
+// Закрывает текущую сессию и отзывает refresh-токен, переданный в заголовке X-Refresh-Token
+func (a *Auth) Logout(w http.ResponseWriter, r *http.Request) {
+	for _, g := range a.granters {
+		err := g.Revoke(w, r)
+		if err != nil {
+			utils.InternalServerError(w, err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// LogoutAll
+// POST /api/user/logout-all
+// Закрывает все сессии и отзывает все refresh-токены пользователя
+func (a *Auth) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	usr := GetUserFromContext(r.Context())
+	for _, g := range a.granters {
+		err := g.RevokeAll(w, r, usr)
+		if err != nil {
+			utils.InternalServerError(w, err)
+			return
+		}
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -104,13 +300,33 @@ func (ar *authRequest) Read(r *http.Request) error {
 	return nil
 }
 
-// GetUserFromContext возвращает сохраненного в контексте пользователя
+//	{
+//		"challenge": "<token>",
+//		"expires_at": "2020-12-10T15:15:45+03:00"
+//	}
+type challengeResponse struct {
+	Challenge string    `json:"challenge"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+//	{
+//		"challenge": "<token>",
+//		"code": "123456"
+//	}
+type loginTwoFactorRequest struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+// GetUserFromContext возвращает сохраненного в контексте пользователя.
+// Роль заполнена, только если на маршруте стоит middleware.Roles
 func GetUserFromContext(ctx context.Context) user.User {
 	if ctx == nil {
 		return user.User{}
 	}
 	if userID, ok := ctx.Value(middleware.ContextUserIDKey).(string); ok {
-		return user.User{ID: userID}
+		role, _ := ctx.Value(middleware.ContextUserRoleKey).(user.Role)
+		return user.User{ID: userID, Role: role}
 	}
 	return user.User{}
 }
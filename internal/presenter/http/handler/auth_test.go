@@ -2,12 +2,15 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
 	mock "github.com/UndeadDemidov/ya-pr-diploma/internal/app/mocks"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
 	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
@@ -29,16 +32,17 @@ func TestAuth_RegisterUser(t *testing.T) {
 		contentType string
 	}
 	tests := []struct {
-		name    string
-		prepare func(f *fields)
-		args    args
-		want    int
+		name     string
+		prepare  func(f *fields)
+		args     args
+		want     int
+		wantRule string
 	}{
 		{
 			name: "status 200",
 			prepare: func(f *fields) {
 				gomock.InOrder(
-					f.auth.EXPECT().SignIn(context.Background(), gomock.Any(), gomock.Any()).Return(nil),
+					f.auth.EXPECT().SignIn(context.Background(), gomock.Any(), gomock.Any()).Return(user.User{ID: "1"}, nil),
 				)
 			},
 			args: args{
@@ -47,6 +51,19 @@ func TestAuth_RegisterUser(t *testing.T) {
 			},
 			want: http.StatusOK,
 		},
+		{
+			name: "status 400 weak password",
+			prepare: func(f *fields) {
+				f.auth.EXPECT().SignIn(context.Background(), gomock.Any(), gomock.Any()).Return(user.User{},
+					&errors2.PolicyViolation{Field: "password", Rule: "strength", Message: "weak"})
+			},
+			args: args{
+				request:     `{"login": "test","password": "test"}`,
+				contentType: utils.ContentTypeJSON,
+			},
+			want:     http.StatusBadRequest,
+			wantRule: "strength",
+		},
 		{
 			name: "status 400 empty body",
 			prepare: func(f *fields) {
@@ -81,7 +98,7 @@ func TestAuth_RegisterUser(t *testing.T) {
 			name: "status 409",
 			prepare: func(f *fields) {
 				gomock.InOrder(
-					f.auth.EXPECT().SignIn(context.Background(), gomock.Any(), gomock.Any()).Return(errors2.ErrLoginIsInUseAlready),
+					f.auth.EXPECT().SignIn(context.Background(), gomock.Any(), gomock.Any()).Return(user.User{}, errors2.ErrLoginIsInUseAlready),
 				)
 			},
 			args: args{
@@ -94,7 +111,7 @@ func TestAuth_RegisterUser(t *testing.T) {
 			name: "status 500",
 			prepare: func(f *fields) {
 				gomock.InOrder(
-					f.auth.EXPECT().SignIn(context.Background(), gomock.Any(), gomock.Any()).Return(errDummy),
+					f.auth.EXPECT().SignIn(context.Background(), gomock.Any(), gomock.Any()).Return(user.User{}, errDummy),
 				)
 			},
 			args: args{
@@ -123,17 +140,31 @@ func TestAuth_RegisterUser(t *testing.T) {
 			request.Header.Set(utils.ContentTypeKey, tt.args.contentType)
 			w := httptest.NewRecorder()
 
-			auth := NewAuth(mockAuth, midware.NewDefaultSessions())
+			auth := NewAuth(mockAuth, mock.NewMockTwoFactorManager(mockCtrl), newTestChallenges(), newTestThrottle(),
+				midware.NewCookieGranter(midware.NewDefaultSessions(), midware.DefaultCookieOptions()))
 			auth.RegisterUser(w, request)
 			result := w.Result()
+			defer result.Body.Close()
 			require.Equal(t, tt.want, result.StatusCode)
+			if tt.wantRule != "" {
+				var body policyViolationResponse
+				require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+				require.Equal(t, tt.wantRule, body.Rule)
+			}
+			if tt.want == http.StatusOK {
+				require.Len(t, result.Cookies(), 1)
+				require.Equal(t, midware.SessionIDCookie, result.Cookies()[0].Name)
+				require.True(t, result.Cookies()[0].HttpOnly)
+				require.Equal(t, http.SameSiteLaxMode, result.Cookies()[0].SameSite)
+			}
 		})
 	}
 }
 
 func TestAuth_LoginUser(t *testing.T) {
 	type fields struct {
-		auth *mock.MockAuthenticator
+		auth      *mock.MockAuthenticator
+		twoFactor *mock.MockTwoFactorManager
 	}
 	type args struct {
 		request     string
@@ -150,6 +181,7 @@ func TestAuth_LoginUser(t *testing.T) {
 			prepare: func(f *fields) {
 				gomock.InOrder(
 					f.auth.EXPECT().Login(context.Background(), gomock.Any(), gomock.Any()).Return(user.User{ID: "1"}, nil),
+					f.twoFactor.EXPECT().IsTwoFactorEnabled(context.Background(), user.User{ID: "1"}).Return(false, nil),
 				)
 			},
 			args: args{
@@ -158,6 +190,20 @@ func TestAuth_LoginUser(t *testing.T) {
 			},
 			want: http.StatusOK,
 		},
+		{
+			name: "status 202 two-factor is enabled",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.auth.EXPECT().Login(context.Background(), gomock.Any(), gomock.Any()).Return(user.User{ID: "1"}, nil),
+					f.twoFactor.EXPECT().IsTwoFactorEnabled(context.Background(), user.User{ID: "1"}).Return(true, nil),
+				)
+			},
+			args: args{
+				request:     `{"login": "test","password": "test"}`,
+				contentType: utils.ContentTypeJSON,
+			},
+			want: http.StatusAccepted,
+		},
 		{
 			name: "status 400 empty body",
 			prepare: func(f *fields) {
@@ -221,9 +267,12 @@ func TestAuth_LoginUser(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 			mockAuth := mock.NewMockAuthenticator(mockCtrl)
+			expectNormalizeLogin(mockAuth)
+			mockTwoFactor := mock.NewMockTwoFactorManager(mockCtrl)
 
 			f := fields{
-				auth: mockAuth,
+				auth:      mockAuth,
+				twoFactor: mockTwoFactor,
 			}
 			if tt.prepare != nil {
 				tt.prepare(&f)
@@ -234,10 +283,257 @@ func TestAuth_LoginUser(t *testing.T) {
 			request.Header.Set(utils.ContentTypeKey, tt.args.contentType)
 			w := httptest.NewRecorder()
 
-			auth := NewAuth(mockAuth, midware.NewDefaultSessions())
+			auth := NewAuth(mockAuth, mockTwoFactor, newTestChallenges(), newTestThrottle(),
+				midware.NewCookieGranter(midware.NewDefaultSessions(), midware.DefaultCookieOptions()))
 			auth.LoginUser(w, request)
 			result := w.Result()
+			defer result.Body.Close()
 			require.Equal(t, tt.want, result.StatusCode)
+			if tt.want == http.StatusAccepted {
+				require.Empty(t, result.Cookies())
+			}
 		})
 	}
 }
+
+func TestAuth_LoginUser_Throttle(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAuth := mock.NewMockAuthenticator(mockCtrl)
+	expectNormalizeLogin(mockAuth)
+	mockAuth.EXPECT().Login(context.Background(), "test", "wrong").Return(user.User{}, errors2.ErrPairLoginPwordIsNotExist).Times(2)
+
+	policy := midware.ThrottlePolicy{Limit: 2, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	auth := NewAuth(mockAuth, mock.NewMockTwoFactorManager(mockCtrl), newTestChallenges(),
+		midware.NewThrottle(midware.NewAttempts(time.Hour), policy, policy),
+		midware.NewCookieGranter(midware.NewDefaultSessions(), midware.DefaultCookieOptions()))
+	login := func(pword string) *http.Response {
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"login": "test","password": "`+pword+`"}`))
+		request.Header.Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+		w := httptest.NewRecorder()
+		auth.LoginUser(w, request)
+		return w.Result()
+	}
+
+	for i := 0; i < 2; i++ {
+		result := login("wrong")
+		result.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	}
+	// даже верный пароль не проверяется, пока логин заблокирован
+	result := login("test")
+	defer result.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, result.StatusCode)
+	require.Equal(t, "60", result.Header.Get(midware.RetryAfterHeader))
+}
+
+func TestAuth_LoginUser_ThrottleNormalizedLogin(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAuth := mock.NewMockAuthenticator(mockCtrl)
+	expectNormalizeLogin(mockAuth)
+	mockAuth.EXPECT().Login(context.Background(), "bob", "wrong").Return(user.User{}, errors2.ErrPairLoginPwordIsNotExist).Times(2)
+
+	policy := midware.ThrottlePolicy{Limit: 2, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	auth := NewAuth(mockAuth, mock.NewMockTwoFactorManager(mockCtrl), newTestChallenges(),
+		midware.NewThrottle(midware.NewAttempts(time.Hour), policy, midware.ThrottlePolicy{}),
+		midware.NewCookieGranter(midware.NewDefaultSessions(), midware.DefaultCookieOptions()))
+	login := func(login, pword string) *http.Response {
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"login": "`+login+`","password": "`+pword+`"}`))
+		request.Header.Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+		w := httptest.NewRecorder()
+		auth.LoginUser(w, request)
+		return w.Result()
+	}
+
+	for _, variant := range []string{"Bob", "BOB"} {
+		result := login(variant, "wrong")
+		result.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+	}
+	result := login("bob", "test")
+	defer result.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, result.StatusCode)
+}
+
+func TestAuth_LoginUser_Token(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAuth := mock.NewMockAuthenticator(mockCtrl)
+	expectNormalizeLogin(mockAuth)
+	mockAuth.EXPECT().Login(context.Background(), "test", "test").Return(user.User{ID: "1"}, nil)
+	mockTwoFactor := mock.NewMockTwoFactorManager(mockCtrl)
+	mockTwoFactor.EXPECT().IsTwoFactorEnabled(context.Background(), user.User{ID: "1"}).Return(false, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"login": "test","password": "test"}`))
+	request.Header.Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+	w := httptest.NewRecorder()
+
+	tokens := midware.NewHS256Tokens(midware.NewRandomKeyring(), time.Hour, "test")
+	auth := NewAuth(mockAuth, mockTwoFactor, newTestChallenges(), newTestThrottle(),
+		midware.NewTokenGranter(tokens, midware.NewRefreshTokens(time.Hour)))
+	auth.LoginUser(w, request)
+	result := w.Result()
+	defer result.Body.Close()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+	require.Empty(t, result.Cookies())
+
+	require.Empty(t, result.Header.Get(midware.AuthorizationHeader))
+	var body midware.TokenResponse
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+	require.Equal(t, "Bearer", body.TokenType)
+	ref, err := tokens.Parse(body.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "1", ref)
+	require.NotEmpty(t, body.RefreshToken)
+}
+
+func TestAuth_LoginTwoFactor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAuth := mock.NewMockAuthenticator(mockCtrl)
+	expectNormalizeLogin(mockAuth)
+	mockAuth.EXPECT().Login(context.Background(), "test", "test").Return(user.User{ID: "1"}, nil)
+	mockTwoFactor := mock.NewMockTwoFactorManager(mockCtrl)
+	mockTwoFactor.EXPECT().IsTwoFactorEnabled(context.Background(), user.User{ID: "1"}).Return(true, nil)
+	gomock.InOrder(
+		mockTwoFactor.EXPECT().VerifyTwoFactor(context.Background(), user.User{ID: "1"}, "000000").
+			Return(errors2.ErrTwoFactorCodeIsInvalid),
+		mockTwoFactor.EXPECT().VerifyTwoFactor(context.Background(), user.User{ID: "1"}, "123456").Return(nil).Times(2),
+	)
+	gomock.InOrder(
+		mockAuth.EXPECT().CheckActive(context.Background(), user.User{ID: "1"}).Return(nil),
+		mockAuth.EXPECT().CheckActive(context.Background(), user.User{ID: "1"}).Return(errors2.ErrUserIsDisabled),
+	)
+
+	auth := NewAuth(mockAuth, mockTwoFactor, newTestChallenges(), newTestThrottle(),
+		midware.NewCookieGranter(midware.NewDefaultSessions(), midware.DefaultCookieOptions()))
+	post := func(handler http.HandlerFunc, body string) *http.Response {
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		request.Header.Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+		w := httptest.NewRecorder()
+		handler(w, request)
+		return w.Result()
+	}
+
+	result := post(auth.LoginUser, `{"login": "test","password": "test"}`)
+	defer result.Body.Close()
+	require.Equal(t, http.StatusAccepted, result.StatusCode)
+	require.Empty(t, result.Cookies())
+	var challenge challengeResponse
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&challenge))
+	require.NotEmpty(t, challenge.Challenge)
+
+	invalid := post(auth.LoginTwoFactor, `{"challenge": "xxx","code": "123456"}`)
+	defer invalid.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, invalid.StatusCode)
+
+	wrong := post(auth.LoginTwoFactor, `{"challenge": "`+challenge.Challenge+`","code": "000000"}`)
+	defer wrong.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, wrong.StatusCode)
+
+	ok := post(auth.LoginTwoFactor, `{"challenge": "`+challenge.Challenge+`","code": "123456"}`)
+	defer ok.Body.Close()
+	require.Equal(t, http.StatusOK, ok.StatusCode)
+	require.Len(t, ok.Cookies(), 1)
+
+	// пользователя отключили, пока действует токен второго шага
+	disabled := post(auth.LoginTwoFactor, `{"challenge": "`+challenge.Challenge+`","code": "123456"}`)
+	defer disabled.Body.Close()
+	require.Equal(t, http.StatusForbidden, disabled.StatusCode)
+	require.Empty(t, disabled.Cookies())
+}
+
+func TestAuth_RefreshToken(t *testing.T) {
+	tokens := midware.NewHS256Tokens(midware.NewRandomKeyring(), time.Hour, "test")
+	refresh := midware.NewRefreshTokens(time.Hour)
+	first, err := refresh.Issue(context.Background(), user.User{ID: "1"})
+	require.NoError(t, err)
+	mockCtrl := gomock.NewController(t)
+	auth := NewAuth(mock.NewMockAuthenticator(mockCtrl), mock.NewMockTwoFactorManager(mockCtrl), newTestChallenges(),
+		newTestThrottle(), midware.NewTokenGranter(tokens, refresh))
+
+	send := func(token access.RefreshToken) *http.Response {
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		request.Header.Set(midware.RefreshTokenHeader, string(token))
+		w := httptest.NewRecorder()
+		auth.RefreshToken(w, request)
+		return w.Result()
+	}
+
+	result := send(first)
+	defer result.Body.Close()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+	var body midware.TokenResponse
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
+	ref, err := tokens.Parse(body.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "1", ref)
+	second := access.RefreshToken(body.RefreshToken)
+	require.NotEqual(t, first, second)
+
+	// повторное предъявление погашенного токена отзывает всю цепочку
+	reused := send(first)
+	defer reused.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, reused.StatusCode)
+	revoked := send(second)
+	defer revoked.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, revoked.StatusCode)
+
+	missing := send("")
+	defer missing.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, missing.StatusCode)
+}
+
+func TestAuth_Logout(t *testing.T) {
+	sessions := midware.NewDefaultSessions()
+	opts := midware.DefaultCookieOptions()
+	granter := midware.NewCookieGranter(sessions, opts)
+	mockCtrl := gomock.NewController(t)
+	auth := NewAuth(mock.NewMockAuthenticator(mockCtrl), mock.NewMockTwoFactorManager(mockCtrl), newTestChallenges(),
+		newTestThrottle(), granter)
+	login := func() *http.Cookie {
+		w := httptest.NewRecorder()
+		_, err := granter.Grant(w, httptest.NewRequest(http.MethodPost, "/", nil), user.User{ID: "1"})
+		require.NoError(t, err)
+		return w.Result().Cookies()[0]
+	}
+	isAlive := func(c *http.Cookie) bool {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.AddCookie(c)
+		w := httptest.NewRecorder()
+		midware.SessionsCookie(sessions, opts.Keys)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(w, request)
+		return w.Code == http.StatusOK
+	}
+
+	current, other := login(), login()
+	request := httptest.NewRequest(http.MethodPost, "/", nil)
+	request.AddCookie(current)
+	w := httptest.NewRecorder()
+	auth.Logout(w, request)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+	require.False(t, isAlive(current))
+	require.True(t, isAlive(other))
+
+	request = httptest.NewRequest(http.MethodPost, "/", nil)
+	request = request.WithContext(context.WithValue(request.Context(), midware.ContextUserIDKey, "1"))
+	w = httptest.NewRecorder()
+	auth.LogoutAll(w, request)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.False(t, isAlive(other))
+}
+
+func newTestChallenges() *midware.Tokens {
+	return midware.NewHS256Tokens(midware.NewRandomKeyring(), time.Minute, "test/2fa")
+}
+
+func newTestThrottle() *midware.Throttle {
+	policy := midware.ThrottlePolicy{Limit: 5, BaseDelay: time.Second, MaxDelay: time.Minute}
+	return midware.NewThrottle(midware.NewAttempts(time.Minute), policy, policy)
+}
+
+// expectNormalizeLogin нормализация логина в тестах - только приведение к нижнему регистру
+func expectNormalizeLogin(m *mock.MockAuthenticator) {
+	m.EXPECT().NormalizeLogin(gomock.Any()).DoAndReturn(strings.ToLower).AnyTimes()
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	OrderNumberParam = "number"
+	LoginQuery       = "login"
+	LimitQuery       = "limit"
+)
+
+var (
+	ErrLoginQueryIsEmpty = errors.New("login query parameter must not be empty")
+	ErrLimitIsInvalid    = errors.New("limit must be positive integer")
+)
+
+// BackOffice просмотр учетных записей для поддержки
+type BackOffice struct {
+	backOffice  app.BackOffice
+	orders      app.OrderProcessor
+	balance     app.BalanceGetter
+	withdrawals app.WithdrawalProcessor
+}
+
+func NewBackOffice(backOffice app.BackOffice, orders app.OrderProcessor, balance app.BalanceGetter,
+	withdrawals app.WithdrawalProcessor) *BackOffice {
+	if backOffice == nil {
+		panic("missing app.BackOffice, parameter must not be nil")
+	}
+	if orders == nil {
+		panic("missing app.OrderProcessor, parameter must not be nil")
+	}
+	if balance == nil {
+		panic("missing app.BalanceGetter, parameter must not be nil")
+	}
+	if withdrawals == nil {
+		panic("missing app.WithdrawalProcessor, parameter must not be nil")
+	}
+	return &BackOffice{backOffice: backOffice, orders: orders, balance: balance, withdrawals: withdrawals}
+}
+
+// FindUsers
+// GET /api/admin/users?login=<подстрока логина>&limit=<сколько вернуть>
+// 200 — найденные учетные записи в JSON;
+// 204 — ничего не найдено;
+// 400 — не задан логин или неверный limit;
+// 500 — внутренняя ошибка сервера.
+func (bo BackOffice) FindUsers(w http.ResponseWriter, r *http.Request) {
+	login := r.URL.Query().Get(LoginQuery)
+	if login == "" {
+		utils.ServerError(w, ErrLoginQueryIsEmpty, http.StatusBadRequest)
+		return
+	}
+	var limit int
+	if val := r.URL.Query().Get(LimitQuery); val != "" {
+		var err error
+		limit, err = strconv.Atoi(val)
+		if err != nil || limit <= 0 {
+			utils.ServerError(w, ErrLimitIsInvalid, http.StatusBadRequest)
+			return
+		}
+	}
+
+	accs, err := bo.backOffice.FindUsers(r.Context(), login, limit)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	if len(accs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, accs)
+}
+
+// Orders
+// GET /api/admin/users/{id}/orders
+// 200 — заказы пользователя со статусами в JSON;
+// 204 — заказов нет;
+// 400 — неверный формат id;
+// 500 — внутренняя ошибка сервера.
+func (bo BackOffice) Orders(w http.ResponseWriter, r *http.Request) {
+	usr, ok := userFromURL(w, r)
+	if !ok {
+		return
+	}
+	ords, err := bo.orders.List(r.Context(), usr)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	if len(ords) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, ords)
+}
+
+// Withdrawals
+// GET /api/admin/users/{id}/withdrawals
+// 200 — списания пользователя в JSON;
+// 204 — списаний нет;
+// 400 — неверный формат id;
+// 500 — внутренняя ошибка сервера.
+func (bo BackOffice) Withdrawals(w http.ResponseWriter, r *http.Request) {
+	usr, ok := userFromURL(w, r)
+	if !ok {
+		return
+	}
+	list, err := bo.withdrawals.List(r.Context(), usr)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	if len(list) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, list)
+}
+
+// Balance
+// GET /api/admin/users/{id}/balance
+// 200 — начислено, списано и текущий остаток в JSON;
+// 400 — неверный формат id;
+// 500 — внутренняя ошибка сервера.
+func (bo BackOffice) Balance(w http.ResponseWriter, r *http.Request) {
+	usr, ok := userFromURL(w, r)
+	if !ok {
+		return
+	}
+	bal, err := bo.balance.Get(r.Context(), usr)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	writeJSON(w, balanceBreakdownResponse{Collected: bal.Collected, Withdrawn: bal.Withdrawn, Current: bal.Current})
+}
+
+// RecheckOrder
+// POST /api/admin/orders/{number}/recheck
+// 200 — заказ сверен с системой расчета начислений, актуальное состояние в JSON;
+// 404 — заказ не найден;
+// 409 — система расчета еще не знает о заказе или заказ уже в конечном статусе;
+// 422 — неверный номер заказа;
+// 503 — система расчета ограничила количество запросов, нужно повторить позже;
+// 500 — внутренняя ошибка сервера.
+func (bo BackOffice) RecheckOrder(w http.ResponseWriter, r *http.Request) {
+	ord, err := bo.backOffice.RecheckOrder(r.Context(), chi.URLParam(r, OrderNumberParam))
+	switch {
+	case errors.Is(err, errors2.ErrOrderInvalidNumberFormat):
+		utils.ServerError(w, err, http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, errors2.ErrOrderNotFound):
+		utils.ServerError(w, err, http.StatusNotFound)
+		return
+	case errors.Is(err, errors2.ErrAccrualOrderIsNotRegistered), errors.Is(err, errors2.ErrOrderIsProcessedAlready):
+		utils.ServerError(w, err, http.StatusConflict)
+		return
+	case errors.Is(err, errors2.ErrAccrualIsBusy):
+		utils.ServerError(w, err, http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		utils.InternalServerError(w, err)
+		return
+	}
+	writeJSON(w, &ord)
+}
+
+//	{
+//		"collected": 1000,
+//		"withdrawn": 42,
+//		"current": 958
+//	}
+type balanceBreakdownResponse struct {
+	Collected primit.Currency `json:"collected"`
+	Withdrawn primit.Currency `json:"withdrawn"`
+	Current   primit.Currency `json:"current"`
+}
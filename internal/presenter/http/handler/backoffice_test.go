@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mock "github.com/UndeadDemidov/ya-pr-diploma/internal/app/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type backOfficeMocks struct {
+	backOffice  *mock.MockBackOffice
+	orders      *mock.MockOrderProcessor
+	balance     *mock.MockBalanceGetter
+	withdrawals *mock.MockWithdrawalProcessor
+}
+
+func newBackOfficeRouter(ctrl *gomock.Controller) (http.Handler, backOfficeMocks) {
+	m := backOfficeMocks{
+		backOffice:  mock.NewMockBackOffice(ctrl),
+		orders:      mock.NewMockOrderProcessor(ctrl),
+		balance:     mock.NewMockBalanceGetter(ctrl),
+		withdrawals: mock.NewMockWithdrawalProcessor(ctrl),
+	}
+	bo := NewBackOffice(m.backOffice, m.orders, m.balance, m.withdrawals)
+	router := chi.NewRouter()
+	router.Get("/api/admin/users", bo.FindUsers)
+	router.Get("/api/admin/users/{id}/orders", bo.Orders)
+	router.Get("/api/admin/users/{id}/withdrawals", bo.Withdrawals)
+	router.Get("/api/admin/users/{id}/balance", bo.Balance)
+	router.Post("/api/admin/orders/{number}/recheck", bo.RecheckOrder)
+	return router, m
+}
+
+func TestBackOffice(t *testing.T) {
+	usr := user.User{ID: testUserID}
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		prepare func(m backOfficeMocks)
+		want    int
+	}{
+		{
+			name:   "find users without login",
+			method: http.MethodGet,
+			path:   "/api/admin/users",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "find users with invalid limit",
+			method: http.MethodGet,
+			path:   "/api/admin/users?login=bob&limit=x",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "users are not found",
+			method: http.MethodGet,
+			path:   "/api/admin/users?login=bob",
+			prepare: func(m backOfficeMocks) {
+				m.backOffice.EXPECT().FindUsers(gomock.Any(), "bob", 0).Return(nil, nil)
+			},
+			want: http.StatusNoContent,
+		},
+		{
+			name:   "users are found",
+			method: http.MethodGet,
+			path:   "/api/admin/users?login=bob&limit=5",
+			prepare: func(m backOfficeMocks) {
+				m.backOffice.EXPECT().FindUsers(gomock.Any(), "bob", 5).Return([]entity.Account{
+					{User: usr, Login: "bob", Registered: time.Now()},
+				}, nil)
+			},
+			want: http.StatusOK,
+		},
+		{
+			name:   "orders of invalid user id",
+			method: http.MethodGet,
+			path:   "/api/admin/users/1/orders",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "orders",
+			method: http.MethodGet,
+			path:   "/api/admin/users/" + testUserID + "/orders",
+			prepare: func(m backOfficeMocks) {
+				m.orders.EXPECT().List(gomock.Any(), usr).Return([]entity.Order{{Number: 12345678903}}, nil)
+			},
+			want: http.StatusOK,
+		},
+		{
+			name:   "no withdrawals",
+			method: http.MethodGet,
+			path:   "/api/admin/users/" + testUserID + "/withdrawals",
+			prepare: func(m backOfficeMocks) {
+				m.withdrawals.EXPECT().List(gomock.Any(), usr).Return(nil, nil)
+			},
+			want: http.StatusNoContent,
+		},
+		{
+			name:   "balance error",
+			method: http.MethodGet,
+			path:   "/api/admin/users/" + testUserID + "/balance",
+			prepare: func(m backOfficeMocks) {
+				m.balance.EXPECT().Get(gomock.Any(), usr).Return(entity.Balance{}, errDummy)
+			},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name:   "recheck invalid number",
+			method: http.MethodPost,
+			path:   "/api/admin/orders/1/recheck",
+			prepare: func(m backOfficeMocks) {
+				m.backOffice.EXPECT().RecheckOrder(gomock.Any(), "1").Return(entity.Order{}, errors2.ErrOrderInvalidNumberFormat)
+			},
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "recheck unknown order",
+			method: http.MethodPost,
+			path:   "/api/admin/orders/12345678903/recheck",
+			prepare: func(m backOfficeMocks) {
+				m.backOffice.EXPECT().RecheckOrder(gomock.Any(), "12345678903").Return(entity.Order{}, errors2.ErrOrderNotFound)
+			},
+			want: http.StatusNotFound,
+		},
+		{
+			name:   "recheck when accrual is busy",
+			method: http.MethodPost,
+			path:   "/api/admin/orders/12345678903/recheck",
+			prepare: func(m backOfficeMocks) {
+				m.backOffice.EXPECT().RecheckOrder(gomock.Any(), "12345678903").Return(entity.Order{}, errors2.ErrAccrualIsBusy)
+			},
+			want: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "recheck processed order",
+			method: http.MethodPost,
+			path:   "/api/admin/orders/12345678903/recheck",
+			prepare: func(m backOfficeMocks) {
+				m.backOffice.EXPECT().RecheckOrder(gomock.Any(), "12345678903").Return(entity.Order{}, errors2.ErrOrderIsProcessedAlready)
+			},
+			want: http.StatusConflict,
+		},
+		{
+			name:   "recheck",
+			method: http.MethodPost,
+			path:   "/api/admin/orders/12345678903/recheck",
+			prepare: func(m backOfficeMocks) {
+				m.backOffice.EXPECT().RecheckOrder(gomock.Any(), "12345678903").
+					Return(entity.Order{Number: 12345678903, Status: entity.Processed, Accrual: 500}, nil)
+			},
+			want: http.StatusOK,
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			router, m := newBackOfficeRouter(mockCtrl)
+			if tt.prepare != nil {
+				tt.prepare(m)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			require.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestBackOffice_Balance(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	router, m := newBackOfficeRouter(mockCtrl)
+	m.balance.EXPECT().Get(gomock.Any(), user.User{ID: testUserID}).
+		Return(entity.Balance{Collected: 1000, Withdrawn: 42, Current: 958}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/users/"+testUserID+"/balance", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var got balanceBreakdownResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	require.Equal(t, balanceBreakdownResponse{Collected: 1000, Withdrawn: 42, Current: 958}, got)
+}
@@ -11,12 +11,11 @@ import (
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
 )
 
-// POST /api/user/order — загрузка пользователем номера заказа для расчёта;
-// GET /api/user/order — получение списка загруженных пользователем номеров заказов, статусов их обработки и информации о начислениях;
+// POST /api/user/orders — загрузка пользователем номера заказа для расчёта;
+// GET /api/user/orders — получение списка загруженных пользователем номеров заказов, статусов их обработки и информации о начислениях;
 
 var (
 	ErrProperOrderNumberIsExpected = errors.New("proper order number is expected")
-	ErrNoContentToReturn           = errors.New("no content to return")
 )
 
 type Order struct {
@@ -94,7 +93,8 @@ func (o *Order) DownloadOrders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if len(list) == 0 {
-		utils.ServerError(w, ErrNoContentToReturn, http.StatusNoContent)
+		w.WriteHeader(http.StatusNoContent)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
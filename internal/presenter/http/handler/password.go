@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+)
+
+type Password struct {
+	pwords   app.PasswordManager
+	granters []middleware.Granter
+}
+
+// NewPassword granters нужны, чтобы после смены пароля закрыть все сессии пользователя
+func NewPassword(pwords app.PasswordManager, granters ...middleware.Granter) *Password {
+	if pwords == nil {
+		panic("missing app.PasswordManager, parameter must not be nil")
+	}
+	if len(granters) == 0 {
+		panic("missing middleware.Granter, at least one must be set")
+	}
+	return &Password{pwords: pwords, granters: granters}
+}
+
+// Change
+// PUT /api/user/password
+// 200 — пароль изменен, остальные сессии пользователя закрыты, текущий клиент получает новый доступ;
+// 400 — неверный формат запроса, пустой новый пароль или пароль не проходит политику;
+// 403 — неверный старый пароль;
+// 500 — внутренняя ошибка сервера.
+func (p Password) Change(w http.ResponseWriter, r *http.Request) {
+	var req changePasswordRequest
+	err := readJSON(r, &req)
+	if err != nil {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	usr := GetUserFromContext(r.Context())
+	if usr.ID == "" {
+		utils.InternalServerError(w, errors2.ErrSessionUserCanNotBeDefined)
+		return
+	}
+
+	err = p.pwords.ChangePassword(r.Context(), usr, req.OldPassword, req.NewPassword)
+	switch {
+	case errors.Is(err, errors2.ErrPasswordIsEmpty):
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	case errors.Is(err, errors2.ErrPasswordIsIncorrect):
+		utils.ServerError(w, err, http.StatusForbidden)
+		return
+	case writePolicyViolation(w, err):
+		return
+	}
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+
+	var body interface{}
+	for _, g := range p.granters {
+		err = g.RevokeAll(w, r, usr)
+		if err != nil {
+			utils.InternalServerError(w, err)
+			return
+		}
+		var granted interface{}
+		granted, err = g.Grant(w, r, usr)
+		if err != nil {
+			utils.InternalServerError(w, err)
+			return
+		}
+		if granted != nil {
+			body = granted
+		}
+	}
+	writeGranted(w, body)
+}
+
+// RequestReset
+// POST /api/user/password/reset
+// 202 — запрос принят, токен отправлен, если логин существует;
+// 400 — неверный формат запроса;
+// 500 — внутренняя ошибка сервера.
+func (p Password) RequestReset(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	err := readJSON(r, &req)
+	if err != nil {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	err = p.pwords.RequestPasswordReset(r.Context(), req.Login)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ConfirmReset
+// POST /api/user/password/reset/confirm
+// 200 — пароль изменен, все сессии пользователя закрыты;
+// 400 — неверный формат запроса, пустой новый пароль, пароль не проходит политику или недействительный токен;
+// 500 — внутренняя ошибка сервера.
+func (p Password) ConfirmReset(w http.ResponseWriter, r *http.Request) {
+	var req confirmResetRequest
+	err := readJSON(r, &req)
+	if err != nil {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	usr, err := p.pwords.ResetPassword(r.Context(), req.Token, req.NewPassword)
+	if errors.Is(err, errors2.ErrPasswordIsEmpty) || errors.Is(err, errors2.ErrResetTokenIsInvalid) {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+	if writePolicyViolation(w, err) {
+		return
+	}
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+
+	for _, g := range p.granters {
+		err = g.RevokeAll(w, r, usr)
+		if err != nil {
+			utils.InternalServerError(w, err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+//	{
+//		"old_password": "<old>",
+//		"new_password": "<new>"
+//	}
+type changePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+//	{
+//		"login": "<login>"
+//	}
+type resetPasswordRequest struct {
+	Login string `json:"login"`
+}
+
+//	{
+//		"token": "<token>",
+//		"new_password": "<new>"
+//	}
+type confirmResetRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	if r.Header.Get(utils.ContentTypeKey) != utils.ContentTypeJSON {
+		return ErrInvalidContentType
+	}
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		return ErrProperJSONIsExpected
+	}
+	return nil
+}
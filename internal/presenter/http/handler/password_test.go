@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mock "github.com/UndeadDemidov/ya-pr-diploma/internal/app/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	midware "github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassword_Change(t *testing.T) {
+	type args struct {
+		contentType string
+		request     string
+	}
+	tests := []struct {
+		name    string
+		prepare func(pwords *mock.MockPasswordManager)
+		args    args
+		want    int
+	}{
+		{
+			name:    "invalid content type",
+			prepare: func(pwords *mock.MockPasswordManager) {},
+			args:    args{contentType: "xxx", request: `{"old_password": "old", "new_password": "new"}`},
+			want:    http.StatusBadRequest,
+		},
+		{
+			name: "empty new password",
+			prepare: func(pwords *mock.MockPasswordManager) {
+				pwords.EXPECT().ChangePassword(gomock.Any(), user.User{ID: "1"}, "old", "").Return(errors2.ErrPasswordIsEmpty)
+			},
+			args: args{contentType: utils.ContentTypeJSON, request: `{"old_password": "old", "new_password": ""}`},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "wrong old password",
+			prepare: func(pwords *mock.MockPasswordManager) {
+				pwords.EXPECT().ChangePassword(gomock.Any(), user.User{ID: "1"}, "old", "new").Return(errors2.ErrPasswordIsIncorrect)
+			},
+			args: args{contentType: utils.ContentTypeJSON, request: `{"old_password": "old", "new_password": "new"}`},
+			want: http.StatusForbidden,
+		},
+		{
+			name: "internal error",
+			prepare: func(pwords *mock.MockPasswordManager) {
+				pwords.EXPECT().ChangePassword(gomock.Any(), user.User{ID: "1"}, "old", "new").Return(errDummy)
+			},
+			args: args{contentType: utils.ContentTypeJSON, request: `{"old_password": "old", "new_password": "new"}`},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "password is changed",
+			prepare: func(pwords *mock.MockPasswordManager) {
+				pwords.EXPECT().ChangePassword(gomock.Any(), user.User{ID: "1"}, "old", "new").Return(nil)
+			},
+			args: args{contentType: utils.ContentTypeJSON, request: `{"old_password": "old", "new_password": "new"}`},
+			want: http.StatusOK,
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			pwords := mock.NewMockPasswordManager(mockCtrl)
+			tt.prepare(pwords)
+
+			sessions := midware.NewDefaultSessions()
+			opts := midware.DefaultCookieOptions()
+			granter := midware.NewCookieGranter(sessions, opts)
+			w := httptest.NewRecorder()
+			_, err := granter.Grant(w, httptest.NewRequest(http.MethodPost, "/", nil), user.User{ID: "1"})
+			require.NoError(t, err)
+			other := w.Result().Cookies()[0]
+
+			request := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tt.args.request))
+			request.Header.Set(utils.ContentTypeKey, tt.args.contentType)
+			request = request.WithContext(context.WithValue(request.Context(), midware.ContextUserIDKey, "1"))
+			w = httptest.NewRecorder()
+
+			NewPassword(pwords, granter).Change(w, request)
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+
+			// после смены пароля другие сессии закрыты, а клиент получил новую
+			check := httptest.NewRequest(http.MethodGet, "/", nil)
+			check.AddCookie(other)
+			cw := httptest.NewRecorder()
+			midware.SessionsCookie(sessions, opts.Keys)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(cw, check)
+			if tt.want == http.StatusOK {
+				require.Equal(t, http.StatusUnauthorized, cw.Code)
+				require.Equal(t, midware.SessionIDCookie, result.Cookies()[len(result.Cookies())-1].Name)
+				require.Greater(t, result.Cookies()[len(result.Cookies())-1].MaxAge, 0)
+				return
+			}
+			require.Equal(t, http.StatusOK, cw.Code)
+		})
+	}
+}
+
+func TestPassword_ConfirmReset(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(pwords *mock.MockPasswordManager)
+		want    int
+	}{
+		{
+			name: "invalid token",
+			prepare: func(pwords *mock.MockPasswordManager) {
+				pwords.EXPECT().ResetPassword(gomock.Any(), "token", "new").Return(user.User{}, errors2.ErrResetTokenIsInvalid)
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "internal error",
+			prepare: func(pwords *mock.MockPasswordManager) {
+				pwords.EXPECT().ResetPassword(gomock.Any(), "token", "new").Return(user.User{}, errDummy)
+			},
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "password is reset",
+			prepare: func(pwords *mock.MockPasswordManager) {
+				pwords.EXPECT().ResetPassword(gomock.Any(), "token", "new").Return(user.User{ID: "1"}, nil)
+			},
+			want: http.StatusOK,
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			pwords := mock.NewMockPasswordManager(mockCtrl)
+			tt.prepare(pwords)
+
+			request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"token": "token", "new_password": "new"}`))
+			request.Header.Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+			w := httptest.NewRecorder()
+
+			NewPassword(pwords, midware.NewCookieGranter(midware.NewDefaultSessions(), midware.DefaultCookieOptions())).ConfirmReset(w, request)
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+		})
+	}
+}
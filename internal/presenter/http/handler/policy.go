@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/rs/zerolog/log"
+)
+
+//	{
+//		"error": "credentials violate policy",
+//		"field": "password",
+//		"rule": "strength",
+//		"message": "password must contain at least 2 of: lowercase, uppercase, digits, other characters"
+//	}
+type policyViolationResponse struct {
+	Error   string `json:"error"`
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// writePolicyViolation отвечает 400 с описанием нарушенного правила, если err - нарушение политики кредов
+func writePolicyViolation(w http.ResponseWriter, err error) bool {
+	var v *errors2.PolicyViolation
+	if !errors.As(err, &v) {
+		return false
+	}
+	w.Header().Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+	w.WriteHeader(http.StatusBadRequest)
+	err = json.NewEncoder(w).Encode(policyViolationResponse{
+		Error:   errors2.ErrCredentialPolicyViolation.Error(),
+		Field:   v.Field,
+		Rule:    v.Rule,
+		Message: v.Message,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("can't write policy violation")
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// TwoFactorCodeHeader заголовок с кодом из приложения для подтверждения крупного списания
+const TwoFactorCodeHeader = "X-TOTP-Code"
+
+// twoFactorThrottleKey неверные коды считаются отдельно от неверных паролей.
+// Двоеточие не проходит политику логинов, поэтому ключ не совпадет ни с одним логином.
+const twoFactorThrottleKey = "2fa:"
+
+type TwoFactor struct {
+	twoFactor app.TwoFactorManager
+	throttle  *middleware.Throttle
+}
+
+// NewTwoFactor throttle ограничивает подбор кодов
+func NewTwoFactor(twoFactor app.TwoFactorManager, throttle *middleware.Throttle) *TwoFactor {
+	if twoFactor == nil {
+		panic("missing app.TwoFactorManager, parameter must not be nil")
+	}
+	if throttle == nil {
+		panic("missing *middleware.Throttle, parameter must not be nil")
+	}
+	return &TwoFactor{twoFactor: twoFactor, throttle: throttle}
+}
+
+// Setup
+// POST /api/user/2fa/setup
+// 200 — секрет и ссылка otpauth:// для приложения-аутентификатора в JSON, 2FA еще не включена;
+// 409 — 2FA уже включена;
+// 500 — внутренняя ошибка сервера.
+func (tf TwoFactor) Setup(w http.ResponseWriter, r *http.Request) {
+	usr := GetUserFromContext(r.Context())
+	if usr.ID == "" {
+		utils.InternalServerError(w, errors2.ErrSessionUserCanNotBeDefined)
+		return
+	}
+
+	setup, err := tf.twoFactor.SetupTwoFactor(r.Context(), usr)
+	if errors.Is(err, errors2.ErrTwoFactorIsEnabledAlready) {
+		utils.ServerError(w, err, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	writeJSON(w, setup)
+}
+
+// Confirm
+// POST /api/user/2fa/confirm
+// 200 — 2FA включена, одноразовые коды восстановления в JSON;
+// 400 — неверный формат запроса или настройка не начата;
+// 403 — неверный код;
+// 409 — 2FA уже включена;
+// 429 — слишком много неверных кодов;
+// 500 — внутренняя ошибка сервера.
+func (tf TwoFactor) Confirm(w http.ResponseWriter, r *http.Request) {
+	var req twoFactorCodeRequest
+	err := readJSON(r, &req)
+	if err != nil {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+	usr := GetUserFromContext(r.Context())
+	if usr.ID == "" {
+		utils.InternalServerError(w, errors2.ErrSessionUserCanNotBeDefined)
+		return
+	}
+
+	var recovery []string
+	confirm := func(ctx context.Context, usr user.User, code string) (err error) {
+		recovery, err = tf.twoFactor.ConfirmTwoFactor(ctx, usr, code)
+		return err
+	}
+	if !checkTwoFactorCode(w, r, tf.throttle, usr, req.Code, confirm, http.StatusForbidden) {
+		return
+	}
+	writeJSON(w, recoveryCodesResponse{RecoveryCodes: recovery})
+}
+
+// Disable
+// POST /api/user/2fa/disable
+// 200 — 2FA выключена;
+// 400 — неверный формат запроса или 2FA не включена;
+// 403 — неверный код;
+// 429 — слишком много неверных кодов;
+// 500 — внутренняя ошибка сервера.
+func (tf TwoFactor) Disable(w http.ResponseWriter, r *http.Request) {
+	var req twoFactorCodeRequest
+	err := readJSON(r, &req)
+	if err != nil {
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
+	}
+	usr := GetUserFromContext(r.Context())
+	if usr.ID == "" {
+		utils.InternalServerError(w, errors2.ErrSessionUserCanNotBeDefined)
+		return
+	}
+
+	if !checkTwoFactorCode(w, r, tf.throttle, usr, req.Code, tf.twoFactor.DisableTwoFactor, http.StatusForbidden) {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// checkTwoFactorCode проверяет код с учетом блокировки после серии неверных кодов.
+// Если код не принят - сам отвечает клиенту и возвращает false. invalidStatus - ответ на неверный код.
+func checkTwoFactorCode(w http.ResponseWriter, r *http.Request, throttle *middleware.Throttle, usr user.User, code string,
+	verify func(ctx context.Context, usr user.User, code string) error, invalidStatus int) bool {
+	key := twoFactorThrottleKey + usr.ID
+	wait, err := throttle.Acquire(r.Context(), key, r)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return false
+	}
+	if wait > 0 {
+		middleware.SetRetryAfter(w, wait)
+		utils.ServerError(w, errors2.ErrTooManyLoginAttempts, http.StatusTooManyRequests)
+		return false
+	}
+
+	err = verify(r.Context(), usr, code)
+	switch {
+	case err == nil:
+		err = throttle.Succeed(r.Context(), key, r)
+		if err != nil {
+			utils.InternalServerError(w, err)
+			return false
+		}
+		return true
+	case errors.Is(err, errors2.ErrTwoFactorCodeIsInvalid):
+		// попытка уже учтена в Acquire
+		utils.ServerError(w, errors2.ErrTwoFactorCodeIsInvalid, invalidStatus)
+		return false
+	}
+
+	releaseErr := throttle.Release(r.Context(), key, r)
+	switch {
+	case releaseErr != nil:
+		utils.InternalServerError(w, releaseErr)
+	case errors.Is(err, errors2.ErrTwoFactorIsNotEnabled):
+		utils.ServerError(w, err, http.StatusBadRequest)
+	case errors.Is(err, errors2.ErrTwoFactorIsEnabledAlready):
+		utils.ServerError(w, err, http.StatusConflict)
+	default:
+		utils.InternalServerError(w, err)
+	}
+	return false
+}
+
+// writeJSON кодирует ответ до отправки статуса, чтобы при ошибке кодирования клиент получил 500, а не 200 с обрывком JSON
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(v)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return
+	}
+	w.Header().Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(buf.Bytes())
+	if err != nil {
+		log.Error().Err(err).Msg("can't write response")
+	}
+}
+
+//	{
+//		"code": "123456"
+//	}
+type twoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+//	{
+//		"recovery_codes": ["abcde-fghij", ...]
+//	}
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	mock "github.com/UndeadDemidov/ya-pr-diploma/internal/app/mocks"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactor_Setup(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		prepare func(twoFactor *mock.MockTwoFactorManager)
+		want    int
+	}{
+		{
+			name: "status 200",
+			prepare: func(twoFactor *mock.MockTwoFactorManager) {
+				twoFactor.EXPECT().SetupTwoFactor(gomock.Any(), usr).
+					Return(app.TwoFactorSetup{Secret: "SECRET", URI: "otpauth://totp/test"}, nil)
+			},
+			want: http.StatusOK,
+		},
+		{
+			name: "status 409",
+			prepare: func(twoFactor *mock.MockTwoFactorManager) {
+				twoFactor.EXPECT().SetupTwoFactor(gomock.Any(), usr).Return(app.TwoFactorSetup{}, errors2.ErrTwoFactorIsEnabledAlready)
+			},
+			want: http.StatusConflict,
+		},
+		{
+			name: "status 500",
+			prepare: func(twoFactor *mock.MockTwoFactorManager) {
+				twoFactor.EXPECT().SetupTwoFactor(gomock.Any(), usr).Return(app.TwoFactorSetup{}, errDummy)
+			},
+			want: http.StatusInternalServerError,
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockTwoFactor := mock.NewMockTwoFactorManager(mockCtrl)
+			tt.prepare(mockTwoFactor)
+
+			request := httptest.NewRequest(http.MethodPost, "/", nil)
+			ctx := context.WithValue(request.Context(), middleware.ContextUserIDKey, usr.ID)
+			w := httptest.NewRecorder()
+
+			NewTwoFactor(mockTwoFactor, newTestThrottle()).Setup(w, request.WithContext(ctx))
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+			if tt.want == http.StatusOK {
+				var setup app.TwoFactorSetup
+				require.NoError(t, json.NewDecoder(result.Body).Decode(&setup))
+				require.Equal(t, "SECRET", setup.Secret)
+			}
+		})
+	}
+}
+
+func TestTwoFactor_Confirm(t *testing.T) {
+	usr := user.User{ID: "1"}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockTwoFactor := mock.NewMockTwoFactorManager(mockCtrl)
+	mockTwoFactor.EXPECT().ConfirmTwoFactor(gomock.Any(), usr, "000000").Return(nil, errors2.ErrTwoFactorCodeIsInvalid).Times(2)
+
+	policy := middleware.ThrottlePolicy{Limit: 2, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	tf := NewTwoFactor(mockTwoFactor, middleware.NewThrottle(middleware.NewAttempts(time.Hour), policy, policy))
+	confirm := func(code string) *http.Response {
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"code": "`+code+`"}`))
+		request.Header.Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+		ctx := context.WithValue(request.Context(), middleware.ContextUserIDKey, usr.ID)
+		w := httptest.NewRecorder()
+		tf.Confirm(w, request.WithContext(ctx))
+		return w.Result()
+	}
+
+	for i := 0; i < 2; i++ {
+		result := confirm("000000")
+		result.Body.Close()
+		require.Equal(t, http.StatusForbidden, result.StatusCode)
+	}
+	// после серии неверных кодов даже верный код не проверяется
+	result := confirm("123456")
+	defer result.Body.Close()
+	require.Equal(t, http.StatusTooManyRequests, result.StatusCode)
+	require.NotEmpty(t, result.Header.Get(middleware.RetryAfterHeader))
+}
+
+func TestTwoFactor_Disable(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		prepare func(twoFactor *mock.MockTwoFactorManager)
+		request string
+		want    int
+	}{
+		{
+			name:    "status 400 invalid json",
+			request: `{"code": `,
+			want:    http.StatusBadRequest,
+		},
+		{
+			name: "status 400 not enabled",
+			prepare: func(twoFactor *mock.MockTwoFactorManager) {
+				twoFactor.EXPECT().DisableTwoFactor(gomock.Any(), usr, "123456").Return(errors2.ErrTwoFactorIsNotEnabled)
+			},
+			request: `{"code": "123456"}`,
+			want:    http.StatusBadRequest,
+		},
+		{
+			name: "status 403",
+			prepare: func(twoFactor *mock.MockTwoFactorManager) {
+				twoFactor.EXPECT().DisableTwoFactor(gomock.Any(), usr, "123456").Return(errors2.ErrTwoFactorCodeIsInvalid)
+			},
+			request: `{"code": "123456"}`,
+			want:    http.StatusForbidden,
+		},
+		{
+			name: "status 200",
+			prepare: func(twoFactor *mock.MockTwoFactorManager) {
+				twoFactor.EXPECT().DisableTwoFactor(gomock.Any(), usr, "123456").Return(nil)
+			},
+			request: `{"code": "123456"}`,
+			want:    http.StatusOK,
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockTwoFactor := mock.NewMockTwoFactorManager(mockCtrl)
+			if tt.prepare != nil {
+				tt.prepare(mockTwoFactor)
+			}
+
+			request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.request))
+			request.Header.Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+			ctx := context.WithValue(request.Context(), middleware.ContextUserIDKey, usr.ID)
+			w := httptest.NewRecorder()
+
+			NewTwoFactor(mockTwoFactor, newTestThrottle()).Disable(w, request.WithContext(ctx))
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeJSON(w, map[string]interface{}{"ch": make(chan int)})
+	result := w.Result()
+	defer result.Body.Close()
+	require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	require.NotEqual(t, utils.ContentTypeJSON, result.Header.Get(utils.ContentTypeKey))
+}
@@ -7,25 +7,40 @@ import (
 
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
 	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
 )
 
 type Withdrawal struct {
 	processor app.WithdrawalProcessor
+	twoFactor app.TwoFactorManager
+	throttle  *middleware.Throttle
+	threshold primit.Currency
 }
 
-func NewWithdrawal(processor app.WithdrawalProcessor) *Withdrawal {
+// NewWithdrawal списание больше threshold пользователь с 2FA подтверждает свежим кодом из приложения
+func NewWithdrawal(processor app.WithdrawalProcessor, twoFactor app.TwoFactorManager, throttle *middleware.Throttle,
+	threshold primit.Currency) *Withdrawal {
 	if processor == nil {
 		panic("missing app.WithdrawalProcessor, parameter must not be nil")
 	}
-	return &Withdrawal{processor: processor}
+	if twoFactor == nil {
+		panic("missing app.TwoFactorManager, parameter must not be nil")
+	}
+	if throttle == nil {
+		panic("missing *middleware.Throttle, parameter must not be nil")
+	}
+	return &Withdrawal{processor: processor, twoFactor: twoFactor, throttle: throttle, threshold: threshold}
 }
 
 // CashOut
 // 200 — успешная обработка запроса;
+// 400 — неверный формат запроса или сумма списания;
 // 402 — на счету недостаточно средств;
-// 422 — неверный номер заказа;
+// 403 — у пользователя включена 2FA, а крупное списание не подтверждено кодом в заголовке X-TOTP-Code или код неверный;
+// 422 — неверный номер заказа или по нему уже было списание;
 // 500 — внутренняя ошибка сервера.
 func (wd Withdrawal) CashOut(w http.ResponseWriter, r *http.Request) {
 	if r.ContentLength == 0 {
@@ -50,14 +65,22 @@ func (wd Withdrawal) CashOut(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Sum > wd.threshold && !wd.confirmed(w, r, usr) {
+		return
+	}
+
 	err = wd.processor.Add(r.Context(), usr, req.Order, req.Sum)
 	switch {
 	case errors.Is(err, errors2.ErrWithdrawalNotEnoughFund):
 		utils.ServerError(w, err, http.StatusPaymentRequired)
 		return
-	case errors.Is(err, errors2.ErrOrderInvalidNumberFormat):
+	case errors.Is(err, errors2.ErrOrderInvalidNumberFormat),
+		errors.Is(err, errors2.ErrWithdrawalOrderAlreadyUsed):
 		utils.ServerError(w, err, http.StatusUnprocessableEntity)
 		return
+	case errors.Is(err, errors2.ErrWithdrawalInvalidSum):
+		utils.ServerError(w, err, http.StatusBadRequest)
+		return
 	}
 	if err != nil {
 		utils.InternalServerError(w, err)
@@ -67,6 +90,24 @@ func (wd Withdrawal) CashOut(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// confirmed если у пользователя включена 2FA, то требует код из приложения. Коды восстановления здесь не принимаются.
+func (wd Withdrawal) confirmed(w http.ResponseWriter, r *http.Request, usr user.User) bool {
+	enabled, err := wd.twoFactor.IsTwoFactorEnabled(r.Context(), usr)
+	if err != nil {
+		utils.InternalServerError(w, err)
+		return false
+	}
+	if !enabled {
+		return true
+	}
+	code := r.Header.Get(TwoFactorCodeHeader)
+	if code == "" {
+		utils.ServerError(w, errors2.ErrTwoFactorCodeIsRequired, http.StatusForbidden)
+		return false
+	}
+	return checkTwoFactorCode(w, r, wd.throttle, usr, code, wd.twoFactor.VerifyTOTP, http.StatusForbidden)
+}
+
 // History
 // 200 — успешная обработка запроса.
 // 204 — нет данных для ответа.
@@ -83,7 +124,8 @@ func (wd Withdrawal) History(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if len(list) == 0 {
-		utils.ServerError(w, ErrNoContentToReturn, http.StatusNoContent)
+		w.WriteHeader(http.StatusNoContent)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -11,6 +11,7 @@ import (
 
 	mock "github.com/UndeadDemidov/ya-pr-diploma/internal/app/mocks"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/entity"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
 	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
@@ -116,6 +117,42 @@ func TestWithdrawal_CashOut(t *testing.T) {
 			},
 			want: http.StatusUnprocessableEntity,
 		},
+		{
+			name: "order is already paid",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.processor.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors2.ErrWithdrawalOrderAlreadyUsed),
+				)
+			},
+			args: args{
+				request: `
+{
+	"order": "2377225624",
+    "sum": 751
+}`,
+				reference:   "1",
+				contentType: utils.ContentTypeJSON,
+			},
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "invalid sum",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.processor.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors2.ErrWithdrawalInvalidSum),
+				)
+			},
+			args: args{
+				request: `
+{
+	"order": "2377225624",
+    "sum": -1
+}`,
+				reference:   "1",
+				contentType: utils.ContentTypeJSON,
+			},
+			want: http.StatusBadRequest,
+		},
 		{
 			name: "not enough fund",
 			prepare: func(f *fields) {
@@ -154,7 +191,8 @@ func TestWithdrawal_CashOut(t *testing.T) {
 			request.Header.Set(utils.ContentTypeKey, tt.args.contentType)
 			w := httptest.NewRecorder()
 
-			wtdrwl := NewWithdrawal(mockWtdrwl)
+			wtdrwl := NewWithdrawal(mockWtdrwl, mock.NewMockTwoFactorManager(mockCtrl), newTestThrottle(),
+				primit.Float64ToCurrency(1000))
 			ctx := context.WithValue(request.Context(), middleware.ContextUserIDKey, tt.args.reference)
 			wtdrwl.CashOut(w, request.WithContext(ctx))
 			result := w.Result()
@@ -163,6 +201,77 @@ func TestWithdrawal_CashOut(t *testing.T) {
 	}
 }
 
+func TestWithdrawal_CashOut_TwoFactor(t *testing.T) {
+	usr := user.User{ID: "1"}
+	tests := []struct {
+		name    string
+		prepare func(twoFactor *mock.MockTwoFactorManager, processor *mock.MockWithdrawalProcessor)
+		code    string
+		want    int
+	}{
+		{
+			name: "two-factor is disabled",
+			prepare: func(twoFactor *mock.MockTwoFactorManager, processor *mock.MockWithdrawalProcessor) {
+				twoFactor.EXPECT().IsTwoFactorEnabled(gomock.Any(), usr).Return(false, nil)
+				processor.EXPECT().Add(gomock.Any(), usr, gomock.Any(), gomock.Any()).Return(nil)
+			},
+			want: http.StatusOK,
+		},
+		{
+			name: "code is required",
+			prepare: func(twoFactor *mock.MockTwoFactorManager, processor *mock.MockWithdrawalProcessor) {
+				twoFactor.EXPECT().IsTwoFactorEnabled(gomock.Any(), usr).Return(true, nil)
+			},
+			want: http.StatusForbidden,
+		},
+		{
+			name: "code is invalid",
+			prepare: func(twoFactor *mock.MockTwoFactorManager, processor *mock.MockWithdrawalProcessor) {
+				twoFactor.EXPECT().IsTwoFactorEnabled(gomock.Any(), usr).Return(true, nil)
+				twoFactor.EXPECT().VerifyTOTP(gomock.Any(), usr, "000000").Return(errors2.ErrTwoFactorCodeIsInvalid)
+			},
+			code: "000000",
+			want: http.StatusForbidden,
+		},
+		{
+			name: "code is valid",
+			prepare: func(twoFactor *mock.MockTwoFactorManager, processor *mock.MockWithdrawalProcessor) {
+				gomock.InOrder(
+					twoFactor.EXPECT().IsTwoFactorEnabled(gomock.Any(), usr).Return(true, nil),
+					twoFactor.EXPECT().VerifyTOTP(gomock.Any(), usr, "123456").Return(nil),
+					processor.EXPECT().Add(gomock.Any(), usr, gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+			code: "123456",
+			want: http.StatusOK,
+		},
+	}
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockTwoFactor := mock.NewMockTwoFactorManager(mockCtrl)
+			mockWtdrwl := mock.NewMockWithdrawalProcessor(mockCtrl)
+			tt.prepare(mockTwoFactor, mockWtdrwl)
+
+			request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"order": "2377225624", "sum": 1001}`))
+			request.Header.Set(utils.ContentTypeKey, utils.ContentTypeJSON)
+			if tt.code != "" {
+				request.Header.Set(TwoFactorCodeHeader, tt.code)
+			}
+			w := httptest.NewRecorder()
+
+			wtdrwl := NewWithdrawal(mockWtdrwl, mockTwoFactor, newTestThrottle(), primit.Float64ToCurrency(1000))
+			ctx := context.WithValue(request.Context(), middleware.ContextUserIDKey, usr.ID)
+			wtdrwl.CashOut(w, request.WithContext(ctx))
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+		})
+	}
+}
+
 func TestWithdrawal_History(t *testing.T) {
 	type fields struct {
 		wtdrwls   []entity.Withdrawal
@@ -274,7 +383,8 @@ func TestWithdrawal_History(t *testing.T) {
 			request := httptest.NewRequest(http.MethodGet, "/", reader)
 			w := httptest.NewRecorder()
 
-			ord := NewWithdrawal(mockWtdrwls)
+			ord := NewWithdrawal(mockWtdrwls, mock.NewMockTwoFactorManager(mockCtrl), newTestThrottle(),
+				primit.Float64ToCurrency(1000))
 			ctx := context.WithValue(request.Context(), middleware.ContextUserIDKey, tt.args.reference)
 			ord.History(w, request.WithContext(ctx))
 			result := w.Result()
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+)
+
+const AdminKeyHeader = "X-Admin-Key"
+
+var (
+	ErrAdminKeyInvalid = errors.New("admin key is not set or invalid")
+	// ContextAdminKeyKey запрос выполнен по ключу администратора, а не пользователем
+	ContextAdminKeyKey = LocalContext("GopherMartAdminKey")
+)
+
+// AdminKey пропускает только запросы с ключом администратора в заголовке X-Admin-Key
+func AdminKey(key string) func(next http.Handler) http.Handler {
+	if key == "" {
+		panic("missing admin key, parameter must not be empty")
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := r.Header.Get(AdminKeyHeader)
+			if subtle.ConstantTimeCompare([]byte(got), []byte(key)) != 1 {
+				utils.ServerError(w, ErrAdminKeyInvalid, http.StatusUnauthorized)
+				return
+			}
+			ctx := context.WithValue(r.Context(), ContextAdminKeyKey, true)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AdminKeyOr запрос с заголовком X-Admin-Key проверяется по ключу и получает роль keyRole,
+// остальные идут через fallback, например, через проверку сессии или токена.
+// Если ключ не задан - все запросы идут через fallback.
+func AdminKeyOr(key string, keyRole user.Role, fallback func(next http.Handler) http.Handler) func(next http.Handler) http.Handler {
+	if fallback == nil {
+		panic("missing middleware, parameter must not be nil")
+	}
+	if key == "" {
+		return fallback
+	}
+	byKey := AdminKey(key)
+	return func(next http.Handler) http.Handler {
+		withRole := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), ContextUserRoleKey, keyRole)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+		withKey, withFallback := byKey(withRole), fallback(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(AdminKeyHeader) != "" {
+				withKey.ServeHTTP(w, r)
+				return
+			}
+			withFallback.ServeHTTP(w, r)
+		})
+	}
+}
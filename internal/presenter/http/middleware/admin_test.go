@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{name: "no key", want: http.StatusUnauthorized},
+		{name: "wrong key", key: "wrong", want: http.StatusUnauthorized},
+		{name: "valid key", key: "secret", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.key != "" {
+				request.Header.Set(AdminKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+
+			AdminKey("secret")(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).ServeHTTP(w, request)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestAdminKeyOr(t *testing.T) {
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+	tests := []struct {
+		name     string
+		adminKey string
+		key      string
+		want     int
+	}{
+		{name: "no key goes to fallback", adminKey: "secret", want: http.StatusForbidden},
+		{name: "wrong key", adminKey: "secret", key: "wrong", want: http.StatusUnauthorized},
+		{name: "valid key", adminKey: "secret", key: "secret", want: http.StatusOK},
+		{name: "admin key is not set", key: "secret", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.key != "" {
+				request.Header.Set(AdminKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+
+			var role user.Role
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				role, _ = r.Context().Value(ContextUserRoleKey).(user.Role)
+			})
+			AdminKeyOr(tt.adminKey, user.RoleAdmin, deny)(next).ServeHTTP(w, request)
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusOK {
+				assert.Equal(t, user.RoleAdmin, role)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/uow"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// contextAuditActorKey через него Audit узнает, кого определила аутентификация внутри цепочки
+var contextAuditActorKey = LocalContext("GopherMartAuditActor")
+
+type auditActor struct {
+	id    string
+	byKey bool
+}
+
+// Audit записывает в журнал каждый запрос к административному API, в том числе отклоненный аутентификацией
+// или проверкой роли. authn - аутентификация административного API, Audit оборачивает ее сам,
+// чтобы в журнал попадали и ответы 401.
+// Журнал не должен терять действий: запрос выполняется в транзакции tx вместе с записью в журнал,
+// ответ придерживается до ее фиксации. Если записать в журнал не удалось - действие откатывается, клиент получает 500.
+func Audit(audit access.AuditLog, tx uow.UnitOfWork, authn func(next http.Handler) http.Handler) func(next http.Handler) http.Handler {
+	if audit == nil {
+		panic("missing access.AuditLog, parameter must not be nil")
+	}
+	if tx == nil {
+		panic("missing uow.UnitOfWork, parameter must not be nil")
+	}
+	if authn == nil {
+		panic("missing middleware, parameter must not be nil")
+	}
+	return func(next http.Handler) http.Handler {
+		authenticated := authn(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if actor, ok := r.Context().Value(contextAuditActorKey).(*auditActor); ok {
+				actor.id, _ = r.Context().Value(ContextUserIDKey).(string)
+				actor.byKey, _ = r.Context().Value(ContextAdminKeyKey).(bool)
+			}
+			next.ServeHTTP(w, r)
+		}))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := &auditActor{}
+			bw := newBufferedWriter()
+			err := tx.Do(r.Context(), func(ctx context.Context) error {
+				ctx = context.WithValue(ctx, contextAuditActorKey, actor)
+				authenticated.ServeHTTP(bw, r.WithContext(ctx))
+				return audit.Record(ctx, access.AuditEntry{
+					ActorID:   actor.id,
+					ByKey:     actor.byKey,
+					Action:    r.Method + " " + routePattern(r),
+					Target:    r.URL.Path,
+					Status:    bw.status,
+					ClientIP:  ClientIP(r),
+					RequestID: chimw.GetReqID(r.Context()),
+					At:        time.Now(),
+				})
+			})
+			if err != nil {
+				utils.InternalServerError(w, err)
+				return
+			}
+			bw.flush(w)
+		})
+	}
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+	return r.URL.Path
+}
+
+// bufferedWriter придерживает ответ, пока не решено, отдавать ли его клиенту
+type bufferedWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newBufferedWriter() *bufferedWriter {
+	// обработчик ничего не записал в ответ - сервер ответит 200
+	return &bufferedWriter{header: make(http.Header), status: http.StatusOK}
+}
+
+func (bw *bufferedWriter) Header() http.Header {
+	return bw.header
+}
+
+func (bw *bufferedWriter) WriteHeader(status int) {
+	if bw.wroteHeader {
+		return
+	}
+	bw.status = status
+	bw.wroteHeader = true
+}
+
+func (bw *bufferedWriter) Write(b []byte) (int, error) {
+	bw.wroteHeader = true
+	return bw.body.Write(b)
+}
+
+func (bw *bufferedWriter) flush(w http.ResponseWriter) {
+	for key, values := range bw.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(bw.status)
+	_, _ = w.Write(bw.body.Bytes())
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditEntries struct {
+	entries []access.AuditEntry
+	err     error
+}
+
+func (a *auditEntries) Record(_ context.Context, entry access.AuditEntry) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+// auditTx запоминает, откатилась ли транзакция
+type auditTx struct {
+	rolledBack bool
+}
+
+func (tx *auditTx) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(ctx)
+	tx.rolledBack = err != nil
+	return err
+}
+
+func newAuditRouter(audit access.AuditLog, tx *auditTx) *chi.Mux {
+	authn := AdminKeyOr("secret", user.RoleAdmin, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), ContextUserIDKey, "1")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	router := chi.NewRouter()
+	router.Group(func(r chi.Router) {
+		r.Use(Audit(audit, tx, authn))
+		r.Post("/api/admin/users/{id}/disable", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		r.Get("/api/admin/users", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("[]"))
+		})
+	})
+	return router
+}
+
+func TestAudit(t *testing.T) {
+	audit := &auditEntries{}
+	router := newAuditRouter(audit, &auditTx{})
+
+	send := func(method, path, key string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			request.Header.Set(AdminKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, request)
+		return w
+	}
+	send(http.MethodPost, "/api/admin/users/42/disable", "")
+	w := send(http.MethodGet, "/api/admin/users", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "[]", w.Body.String())
+	// неверный ключ тоже попадает в журнал
+	w = send(http.MethodGet, "/api/admin/users", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	got := audit.entries
+	if assert.Len(t, got, 3) {
+		assert.Equal(t, "1", got[0].ActorID)
+		assert.False(t, got[0].ByKey)
+		assert.Equal(t, "POST /api/admin/users/{id}/disable", got[0].Action)
+		assert.Equal(t, "/api/admin/users/42/disable", got[0].Target)
+		assert.Equal(t, http.StatusNotFound, got[0].Status)
+
+		assert.Empty(t, got[1].ActorID)
+		assert.True(t, got[1].ByKey)
+		assert.Equal(t, http.StatusOK, got[1].Status)
+
+		assert.Empty(t, got[2].ActorID)
+		assert.False(t, got[2].ByKey)
+		assert.Equal(t, http.StatusUnauthorized, got[2].Status)
+	}
+}
+
+func TestAudit_FailClosed(t *testing.T) {
+	tx := &auditTx{}
+	router := newAuditRouter(&auditEntries{err: errors.New("audit log is unavailable")}, tx)
+
+	request := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+	request.Header.Set(AdminKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "[]")
+	assert.True(t, tx.rolledBack)
+}
@@ -10,81 +10,116 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
 	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
 )
 
 const (
 	SessionIDCookie = "GopherMartSessionID"
-	salt            = "secret key" // Можно использовать IP или еще что-то присущее конкретному пользователю/машине
-	saltStartIdx    = 4
-	saltEndIdx      = 9
-	maxAge          = 60 * 60 * 24 * 180
 )
 
 var (
 	ErrSignedCookieInvalidValueOrUnsigned = errors.New("invalid cookie value or it is unsigned")
 	ErrSignedCookieInvalidSign            = errors.New("invalid sign")
-	ErrSignedCookieSaltNotSetProperly     = errors.New("SaltStartIdx and SaltEndIdx must be set properly")
+	ErrSignedCookieUnknownKey             = errors.New("cookie is signed with unknown key")
 	ContextUserIDKey                      = LocalContext(SessionIDCookie)
 )
 
 type LocalContext string
 
-func SessionsCookie(sessions *Sessions) func(next http.Handler) http.Handler {
+func SessionsCookie(sessions access.SessionStore, keys *Keyring) func(next http.Handler) http.Handler {
 	if sessions == nil {
-		panic("missing *Sessions, parameter must not be nil")
+		panic("missing access.SessionStore, parameter must not be nil")
+	}
+	if keys == nil {
+		panic("missing *Keyring, parameter must not be nil")
 	}
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// получить из куки id сессии
-			token, err := getSessionTokenFromCookie(SessionIDCookie, r)
+			token, err := getSessionTokenFromCookie(SessionIDCookie, keys, r)
 			// если сессии нет - прерываем работу
 			if err != nil {
 				utils.ServerError(w, err, http.StatusUnauthorized)
 				return
 			}
-			// если сессия есть - проверяем валидность
+			// если сессия есть - проверяем валидность и продлеваем
 			// если не валидна - прерываем работу
-			if sessions.IsExpired(token) {
-				utils.ServerError(w, errors2.ErrSessionIsExpired, http.StatusUnauthorized)
+			ref, err := sessions.GetReference(r.Context(), token)
+			if err != nil {
+				if errors.Is(err, errors2.ErrSessionIsExpired) {
+					utils.ServerError(w, errors2.ErrSessionIsExpired, http.StatusUnauthorized)
+					return
+				}
+				utils.InternalServerError(w, err)
 				return
 			}
 			// если сессия валидна - ID пользователя в контекст
-			ctx := context.WithValue(r.Context(), ContextUserIDKey, sessions.GetReference(token))
+			ctx := context.WithValue(r.Context(), ContextUserIDKey, ref)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
-func getSessionTokenFromCookie(name string, r *http.Request) (token SessionToken, err error) {
+func getSessionTokenFromCookie(name string, keys *Keyring, r *http.Request) (token access.SessionToken, err error) {
 	c, err := r.Cookie(name)
 	if err != nil {
 		return "", err
 	}
-	cookie := NewSessionSignedCookie("")
-	cookie.Cookie = c
+	cookie := SignedCookie{Cookie: c, keys: keys}
 	err = cookie.DetachSign()
 	if err != nil {
 		return "", err
 	}
-	return SessionToken(cookie.BaseValue), nil
+	return access.SessionToken(cookie.BaseValue), nil
+}
+
+// CookieOptions атрибуты сессионной куки
+type CookieOptions struct {
+	// Keys ключи, которыми подписывается и проверяется кука
+	Keys *Keyring
+	// MaxAge время жизни куки в секундах
+	MaxAge   int
+	Domain   string
+	Secure   bool
+	HTTPOnly bool
+	SameSite http.SameSite
+}
+
+// DefaultCookieOptions кука живет столько же, сколько и сессия, недоступна из JS
+// и подписана случайным ключом
+func DefaultCookieOptions() CookieOptions {
+	return CookieOptions{
+		Keys:     NewRandomKeyring(),
+		MaxAge:   int(DefaultSessionLifetime.Seconds()),
+		HTTPOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
 }
 
+// SignedCookie кука вида <значение>|<id ключа>|<подпись>
 type SignedCookie struct {
 	*http.Cookie
-	SaltStartIdx uint
-	SaltEndIdx   uint
-	key          []byte
-	sign         []byte
-	BaseValue    string
+	keys      *Keyring
+	KeyID     string
+	sign      []byte
+	BaseValue string
 }
 
-func NewSessionSignedCookie(val SessionToken) (sc SignedCookie) {
-	return NewSignedCookie("/", SessionIDCookie, string(val), maxAge, saltStartIdx, saltEndIdx)
+func NewSessionSignedCookie(val access.SessionToken, opts CookieOptions) (sc SignedCookie) {
+	sc = NewSignedCookie("/", SessionIDCookie, string(val), opts.MaxAge, opts.Keys)
+	sc.Domain = opts.Domain
+	sc.Secure = opts.Secure
+	sc.HttpOnly = opts.HTTPOnly
+	sc.SameSite = opts.SameSite
+	return sc
 }
 
-func NewSignedCookie(path, name, val string, maxAge int, saltStartIdx, saltEndIdx uint) (sc SignedCookie) {
+func NewSignedCookie(path, name, val string, maxAge int, keys *Keyring) (sc SignedCookie) {
+	if keys == nil {
+		panic("missing *Keyring, parameter must not be nil")
+	}
 	sc = SignedCookie{
 		Cookie: &http.Cookie{
 			Path:   path,
@@ -92,56 +127,52 @@ func NewSignedCookie(path, name, val string, maxAge int, saltStartIdx, saltEndId
 			Value:  val,
 			MaxAge: maxAge,
 		},
-		SaltStartIdx: saltStartIdx,
-		SaltEndIdx:   saltEndIdx,
+		keys: keys,
 	}
 
 	sc.AttachSign()
 	return sc
 }
 
+// AttachSign подписывает значение куки текущим ключом
 func (sc *SignedCookie) AttachSign() {
 	sc.BaseValue = sc.Value
-	if len(sc.key) == 0 {
-		sc.RecalcKey()
-	}
-	sc.sign = sc.calcSign()
-	sc.Value = fmt.Sprintf("%s|%s", sc.Value, hex.EncodeToString(sc.sign))
-}
-
-func (sc *SignedCookie) calcSign() []byte {
-	h := hmac.New(sha256.New, sc.key)
-	h.Write([]byte(sc.BaseValue))
-	return h.Sum(nil)
-}
-
-func (sc *SignedCookie) RecalcKey() {
-	if sc.SaltStartIdx == 0 || sc.SaltEndIdx == 0 ||
-		sc.SaltEndIdx < sc.SaltStartIdx || sc.SaltEndIdx > uint(len(sc.BaseValue)) {
-		panic(ErrSignedCookieSaltNotSetProperly)
-	}
-
-	var key = []byte(salt)
-	key = append(key, []byte(sc.BaseValue)[sc.SaltStartIdx:sc.SaltEndIdx]...)
-	sc.key = key
+	var key []byte
+	sc.KeyID, key = sc.keys.current()
+	sc.sign = calcSign(key, sc.BaseValue)
+	sc.Value = fmt.Sprintf("%s|%s|%s", sc.BaseValue, sc.KeyID, hex.EncodeToString(sc.sign))
 }
 
+// DetachSign проверяет подпись ключом, id которого указан в куке
 func (sc *SignedCookie) DetachSign() (err error) {
 	ss := strings.Split(sc.Value, "|")
-	if len(ss) < 2 {
+	if len(ss) != 3 {
 		return ErrSignedCookieInvalidValueOrUnsigned
 	}
-	sc.BaseValue = ss[0]
-	sc.RecalcKey()
-
-	sign := ss[1]
-	if hex.EncodeToString(sc.calcSign()) != sign {
+	key, ok := sc.keys.key(ss[1])
+	if !ok {
+		return ErrSignedCookieUnknownKey
+	}
+	sign, err := hex.DecodeString(ss[2])
+	if err != nil {
+		return ErrSignedCookieInvalidSign
+	}
+	if !hmac.Equal(calcSign(key, ss[0]), sign) {
 		return ErrSignedCookieInvalidSign
 	}
 
+	sc.BaseValue = ss[0]
+	sc.KeyID = ss[1]
+	sc.sign = sign
 	return nil
 }
 
 func (sc *SignedCookie) Set(w http.ResponseWriter) {
 	http.SetCookie(w, sc.Cookie)
 }
+
+func calcSign(key []byte, val string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(val))
+	return h.Sum(nil)
+}
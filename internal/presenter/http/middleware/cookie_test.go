@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSessionSignedCookie(t *testing.T) {
+	opts := CookieOptions{
+		Keys:     NewRandomKeyring(),
+		MaxAge:   3600,
+		Domain:   "example.com",
+		Secure:   true,
+		HTTPOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	}
+	c := NewSessionSignedCookie(access.NewSessionToken(), opts)
+	assert.Equal(t, SessionIDCookie, c.Name)
+	assert.Equal(t, 3600, c.MaxAge)
+	assert.Equal(t, "example.com", c.Domain)
+	assert.True(t, c.Secure)
+	assert.True(t, c.HttpOnly)
+	assert.Equal(t, http.SameSiteStrictMode, c.SameSite)
+}
+
+func TestSessionsCookie(t *testing.T) {
+	sessions := NewDefaultSessions()
+	token, err := sessions.AddNewSession(context.Background(), testRef("user"))
+	require.NoError(t, err)
+	opts := DefaultCookieOptions()
+	valid := NewSessionSignedCookie(token, opts).Cookie
+
+	forged := *valid
+	forged.Value = string(access.NewSessionToken()) + forged.Value[len(token):]
+
+	unknown := NewSessionSignedCookie(access.NewSessionToken(), opts).Cookie
+
+	foreign := NewSessionSignedCookie(token, DefaultCookieOptions()).Cookie
+
+	tests := []struct {
+		name    string
+		cookie  *http.Cookie
+		want    int
+		wantRef string
+	}{
+		{name: "no cookie", want: http.StatusUnauthorized},
+		{name: "valid session", cookie: valid, want: http.StatusOK, wantRef: "user"},
+		{name: "forged sign", cookie: &forged, want: http.StatusUnauthorized},
+		{name: "unknown session", cookie: unknown, want: http.StatusUnauthorized},
+		{name: "signed with foreign key", cookie: foreign, want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ref interface{}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ref = r.Context().Value(ContextUserIDKey)
+			})
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != nil {
+				request.AddCookie(tt.cookie)
+			}
+			w := httptest.NewRecorder()
+
+			SessionsCookie(sessions, opts.Keys)(next).ServeHTTP(w, request)
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+			if tt.wantRef != "" {
+				assert.Equal(t, tt.wantRef, ref)
+			}
+		})
+	}
+}
+
+func TestSignedCookie_DetachSign(t *testing.T) {
+	oldKey := []byte(strings.Repeat("o", minSigningKeyLen))
+	newKey := []byte(strings.Repeat("n", minSigningKeyLen))
+	before, err := NewKeyring("1", map[string][]byte{"1": oldKey})
+	require.NoError(t, err)
+	rotated, err := NewKeyring("2", map[string][]byte{"1": oldKey, "2": newKey})
+	require.NoError(t, err)
+	retired, err := NewKeyring("2", map[string][]byte{"2": newKey})
+	require.NoError(t, err)
+
+	signed := NewSignedCookie("/", "test", "value", 0, before)
+	tests := []struct {
+		name    string
+		value   string
+		keys    *Keyring
+		wantErr error
+	}{
+		{name: "same key", value: signed.Value, keys: before},
+		{name: "old key after rotation", value: signed.Value, keys: rotated},
+		{name: "retired key", value: signed.Value, keys: retired, wantErr: ErrSignedCookieUnknownKey},
+		{name: "unsigned", value: "value", keys: rotated, wantErr: ErrSignedCookieInvalidValueOrUnsigned},
+		{name: "sign is not hex", value: "value|1|zz", keys: rotated, wantErr: ErrSignedCookieInvalidSign},
+		{name: "tampered value", value: "eulav" + signed.Value[len("value"):], keys: rotated, wantErr: ErrSignedCookieInvalidSign},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := SignedCookie{Cookie: &http.Cookie{Value: tt.value}, keys: tt.keys}
+			err := sc.DetachSign()
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.Equal(t, "value", sc.BaseValue)
+				assert.Equal(t, "1", sc.KeyID)
+			}
+		})
+	}
+}
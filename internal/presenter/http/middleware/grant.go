@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+)
+
+const RefreshTokenHeader = "X-Refresh-Token"
+
+// Granter выдает аутентифицированному пользователю доступ к закрытым ресурсам и отзывает его
+type Granter interface {
+	// Grant если доступ передается в теле ответа - возвращает это тело, обработчик сам запишет его в ответ
+	Grant(w http.ResponseWriter, r *http.Request, userRef access.Referencer) (body interface{}, err error)
+	// Revoke отзывает доступ, предъявленный в запросе
+	Revoke(w http.ResponseWriter, r *http.Request) error
+	// RevokeAll отзывает все доступы пользователя, выданные ему на любых устройствах
+	RevokeAll(w http.ResponseWriter, r *http.Request, userRef access.Referencer) error
+}
+
+// Refresher продлевает доступ без повторного ввода пароля
+type Refresher interface {
+	// Refresh возвращает тело ответа с новым доступом, как и Granter.Grant
+	Refresh(w http.ResponseWriter, r *http.Request) (body interface{}, err error)
+}
+
+var (
+	_ Granter   = (*CookieGranter)(nil)
+	_ Granter   = (*TokenGranter)(nil)
+	_ Refresher = (*TokenGranter)(nil)
+)
+
+// CookieGranter открывает сессию пользователя и отдает ее в подписанной куке
+type CookieGranter struct {
+	sessions access.SessionStore
+	opts     CookieOptions
+}
+
+func NewCookieGranter(sessions access.SessionStore, opts CookieOptions) *CookieGranter {
+	if sessions == nil {
+		panic("missing access.SessionStore, parameter must not be nil")
+	}
+	if opts.Keys == nil {
+		panic("missing CookieOptions.Keys, parameter must not be nil")
+	}
+	return &CookieGranter{sessions: sessions, opts: opts}
+}
+
+// Grant сессия передается только в куке, тела ответа нет
+func (g *CookieGranter) Grant(w http.ResponseWriter, r *http.Request, userRef access.Referencer) (interface{}, error) {
+	token, err := g.sessions.AddNewSession(r.Context(), userRef)
+	if err != nil {
+		return nil, err
+	}
+	cookie := NewSessionSignedCookie(token, g.opts)
+	cookie.Set(w)
+	return nil, nil
+}
+
+func (g *CookieGranter) Revoke(w http.ResponseWriter, r *http.Request) error {
+	token, err := getSessionTokenFromCookie(SessionIDCookie, g.opts.Keys, r)
+	if err != nil {
+		// нечего закрывать
+		return nil
+	}
+	err = g.sessions.DeleteSession(r.Context(), token)
+	if err != nil {
+		return err
+	}
+	g.expireCookie(w)
+	return nil
+}
+
+func (g *CookieGranter) RevokeAll(w http.ResponseWriter, r *http.Request, userRef access.Referencer) error {
+	err := g.sessions.DeleteUserSessions(r.Context(), userRef.Reference())
+	if err != nil {
+		return err
+	}
+	g.expireCookie(w)
+	return nil
+}
+
+// expireCookie просит браузер удалить сессионную куку
+func (g *CookieGranter) expireCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Path:     "/",
+		Name:     SessionIDCookie,
+		MaxAge:   -1,
+		Domain:   g.opts.Domain,
+		Secure:   g.opts.Secure,
+		HttpOnly: g.opts.HTTPOnly,
+		SameSite: g.opts.SameSite,
+	})
+}
+
+// TokenResponse тело ответа с парой токенов. JWT предъявляется в заголовке Authorization: Bearer,
+// refresh-токен - в заголовке X-Refresh-Token
+//
+//	{
+//		"access_token": "<jwt>",
+//		"token_type": "Bearer",
+//		"expires_at": "2022-06-01T12:00:00Z",
+//		"refresh_token": "<token>"
+//	}
+type TokenResponse struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+}
+
+// TokenGranter отдает JWT и refresh-токен в теле ответа
+type TokenGranter struct {
+	tokens  *Tokens
+	refresh access.RefreshTokenStore
+}
+
+func NewTokenGranter(tokens *Tokens, refresh access.RefreshTokenStore) *TokenGranter {
+	if tokens == nil {
+		panic("missing *Tokens, parameter must not be nil")
+	}
+	if refresh == nil {
+		panic("missing access.RefreshTokenStore, parameter must not be nil")
+	}
+	return &TokenGranter{tokens: tokens, refresh: refresh}
+}
+
+func (g *TokenGranter) Grant(_ http.ResponseWriter, r *http.Request, userRef access.Referencer) (interface{}, error) {
+	refresh, err := g.refresh.Issue(r.Context(), userRef)
+	if err != nil {
+		return nil, err
+	}
+	return g.tokenResponse(userRef, refresh)
+}
+
+// Refresh обменивает refresh-токен из заголовка X-Refresh-Token на новую пару токенов
+func (g *TokenGranter) Refresh(_ http.ResponseWriter, r *http.Request) (interface{}, error) {
+	token := access.RefreshToken(r.Header.Get(RefreshTokenHeader))
+	if token == "" {
+		return nil, ErrRefreshTokenNotSet
+	}
+	ref, next, err := g.refresh.Rotate(r.Context(), token)
+	if err != nil {
+		return nil, err
+	}
+	return g.tokenResponse(reference(ref), next)
+}
+
+// Revoke отзывает цепочку refresh-токена из заголовка X-Refresh-Token.
+// Уже выданный JWT остается действительным до истечения своего короткого срока жизни.
+func (g *TokenGranter) Revoke(_ http.ResponseWriter, r *http.Request) error {
+	token := access.RefreshToken(r.Header.Get(RefreshTokenHeader))
+	if token == "" {
+		return nil
+	}
+	return g.refresh.RevokeFamily(r.Context(), token)
+}
+
+func (g *TokenGranter) RevokeAll(_ http.ResponseWriter, r *http.Request, userRef access.Referencer) error {
+	return g.refresh.RevokeUser(r.Context(), userRef.Reference())
+}
+
+// tokenResponse возвращает интерфейс, а не *TokenResponse, чтобы при ошибке тело было именно nil
+func (g *TokenGranter) tokenResponse(userRef access.Referencer, refresh access.RefreshToken) (interface{}, error) {
+	token, expiresAt, err := g.tokens.Issue(userRef)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenResponse{AccessToken: token, TokenType: bearerScheme, ExpiresAt: expiresAt, RefreshToken: string(refresh)}, nil
+}
+
+// reference ссылка на пользователя, уже сохраненная в хранилище
+type reference string
+
+func (r reference) Reference() string {
+	return string(r)
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const janitorInterval = 1 * time.Minute
+
+// ExpiredDeleter хранилище, из которого периодически вычищаются истекшие записи
+type ExpiredDeleter interface {
+	DeleteExpired(ctx context.Context) error
+}
+
+// Janitor периодически удаляет истекшие сессии и токены из хранилищ
+type Janitor struct {
+	stores   []ExpiredDeleter
+	interval time.Duration
+}
+
+func NewJanitor(stores ...ExpiredDeleter) *Janitor {
+	for _, store := range stores {
+		if store == nil {
+			panic("missing ExpiredDeleter, parameter must not be nil")
+		}
+	}
+	return &Janitor{stores: stores, interval: janitorInterval}
+}
+
+// Run блокируется до отмены контекста
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("janitor stopped")
+			return
+		case <-ticker.C:
+			for _, store := range j.stores {
+				err := store.DeleteExpired(ctx)
+				if err != nil && ctx.Err() == nil {
+					log.Error().Err(err).Msg("can't delete expired records")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+)
+
+const (
+	minSigningKeyLen = 32
+	randomKeyID      = "ephemeral"
+)
+
+var (
+	ErrKeyringCurrentKeyNotSet = errors.New("current signing key is not set")
+	ErrKeyringKeyTooShort      = errors.New("signing key is too short, at least 32 bytes are expected")
+	ErrKeyringInvalidKeyID     = errors.New("signing key id must be non-empty and must not contain '|'")
+)
+
+// Keyring набор ключей для подписи кук.
+// Подписывается всегда текущим ключом, а проверяется любым из набора - так ключи можно ротировать,
+// не разлогинивая пользователей. Старый ключ нужно держать в наборе не меньше срока жизни сессии.
+type Keyring struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+func NewKeyring(currentID string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[currentID]; !ok {
+		return nil, ErrKeyringCurrentKeyNotSet
+	}
+	k := &Keyring{currentID: currentID, keys: make(map[string][]byte, len(keys))}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, "|") {
+			return nil, ErrKeyringInvalidKeyID
+		}
+		if len(key) < minSigningKeyLen {
+			return nil, ErrKeyringKeyTooShort
+		}
+		k.keys[id] = append([]byte(nil), key...)
+	}
+	return k, nil
+}
+
+// NewRandomKeyring набор из одного случайного ключа. Сессии не переживут перезапуск сервиса
+// и не будут приниматься другими его экземплярами.
+func NewRandomKeyring() *Keyring {
+	key := make([]byte, minSigningKeyLen)
+	_, err := rand.Read(key)
+	if err != nil {
+		panic(err)
+	}
+	return &Keyring{currentID: randomKeyID, keys: map[string][]byte{randomKeyID: key}}
+}
+
+func (k *Keyring) current() (id string, key []byte) {
+	return k.currentID, k.keys[k.currentID]
+}
+
+func (k *Keyring) key(id string) (key []byte, ok bool) {
+	key, ok = k.keys[id]
+	return key, ok
+}
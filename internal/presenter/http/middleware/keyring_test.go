@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewKeyring(t *testing.T) {
+	key := []byte(strings.Repeat("k", minSigningKeyLen))
+	tests := []struct {
+		name    string
+		current string
+		keys    map[string][]byte
+		wantErr error
+	}{
+		{name: "valid", current: "1", keys: map[string][]byte{"1": key}},
+		{name: "current key is missing", current: "2", keys: map[string][]byte{"1": key}, wantErr: ErrKeyringCurrentKeyNotSet},
+		{name: "short key", current: "1", keys: map[string][]byte{"1": key[:8]}, wantErr: ErrKeyringKeyTooShort},
+		{name: "invalid id", current: "1", keys: map[string][]byte{"1": key, "a|b": key}, wantErr: ErrKeyringInvalidKeyID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyring(tt.current, tt.keys)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
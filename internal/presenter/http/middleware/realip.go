@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+var (
+	xForwardedFor = http.CanonicalHeaderKey("X-Forwarded-For")
+	xRealIP       = http.CanonicalHeaderKey("X-Real-IP")
+)
+
+// RealIP подменяет RemoteAddr адресом клиента из X-Forwarded-For или X-Real-IP,
+// только если соединение пришло от доверенного прокси. Иначе заголовки задает сам клиент
+// и мог бы менять в них IP после каждой блокировки.
+// В X-Forwarded-For берется самый правый недоверенный адрес: левее него значения мог дописать клиент.
+func RealIP(trusted []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if isTrusted(trusted, net.ParseIP(ClientIP(r))) {
+				if ip := forwardedIP(trusted, r); ip != "" {
+					r.RemoteAddr = ip
+				}
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func forwardedIP(trusted []*net.IPNet, r *http.Request) string {
+	if xff := r.Header.Values(xForwardedFor); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		var first string
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				// дальше цепочке доверять нельзя
+				break
+			}
+			first = ip.String()
+			if !isTrusted(trusted, ip) {
+				return first
+			}
+		}
+		// все адреса цепочки доверенные - клиент самый левый из них
+		if first != "" {
+			return first
+		}
+	}
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get(xRealIP))); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+func isTrusted(trusted []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+)
+
+const DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+type refreshToken struct {
+	family  string
+	userRef string
+	expiry  time.Time
+	used    bool
+	revoked bool
+}
+
+var _ access.RefreshTokenStore = (*RefreshTokens)(nil)
+
+// RefreshTokens хранит refresh-токены в памяти. Все токены теряются при перезапуске сервиса.
+type RefreshTokens struct {
+	ttl   time.Duration
+	mu    sync.Mutex
+	store map[access.RefreshToken]refreshToken
+}
+
+func NewRefreshTokens(ttl time.Duration) *RefreshTokens {
+	return &RefreshTokens{
+		ttl:   ttl,
+		store: make(map[access.RefreshToken]refreshToken, 8),
+	}
+}
+
+func (rt *RefreshTokens) Issue(_ context.Context, userRef access.Referencer) (access.RefreshToken, error) {
+	if userRef == nil {
+		panic("missing access.Referencer, parameter must not be nil")
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.add(access.NewRefreshTokenFamily(), userRef.Reference()), nil
+}
+
+func (rt *RefreshTokens) Rotate(_ context.Context, token access.RefreshToken) (string, access.RefreshToken, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	t, ok := rt.store[token]
+	if !ok || t.revoked || t.expiry.Before(time.Now()) {
+		return "", "", errors2.ErrRefreshTokenIsInvalid
+	}
+	if t.used {
+		rt.revoke(func(other refreshToken) bool { return other.family == t.family })
+		return "", "", errors2.ErrRefreshTokenIsReused
+	}
+	t.used = true
+	rt.store[token] = t
+	return t.userRef, rt.add(t.family, t.userRef), nil
+}
+
+func (rt *RefreshTokens) RevokeFamily(_ context.Context, token access.RefreshToken) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	t, ok := rt.store[token]
+	if !ok {
+		return nil
+	}
+	rt.revoke(func(other refreshToken) bool { return other.family == t.family })
+	return nil
+}
+
+func (rt *RefreshTokens) RevokeUser(_ context.Context, userRef string) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.revoke(func(other refreshToken) bool { return other.userRef == userRef })
+	return nil
+}
+
+func (rt *RefreshTokens) DeleteExpired(_ context.Context) error {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	now := time.Now()
+	for token, t := range rt.store {
+		if t.expiry.Before(now) {
+			delete(rt.store, token)
+		}
+	}
+	return nil
+}
+
+// add и revoke вызываются под блокировкой
+func (rt *RefreshTokens) add(family, userRef string) access.RefreshToken {
+	token := access.NewRefreshToken()
+	rt.store[token] = refreshToken{
+		family:  family,
+		userRef: userRef,
+		expiry:  time.Now().Add(rt.ttl),
+	}
+	return token
+}
+
+func (rt *RefreshTokens) revoke(match func(t refreshToken) bool) {
+	for token, t := range rt.store {
+		if match(t) {
+			t.revoked = true
+			rt.store[token] = t
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshTokens_Rotate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		prepare func(rt *RefreshTokens, token access.RefreshToken)
+		wantErr error
+	}{
+		{name: "fresh token", ttl: time.Hour},
+		{name: "expired token", ttl: -time.Second, wantErr: errors2.ErrRefreshTokenIsInvalid},
+		{
+			name: "revoked family",
+			ttl:  time.Hour,
+			prepare: func(rt *RefreshTokens, token access.RefreshToken) {
+				require.NoError(t, rt.RevokeFamily(context.Background(), token))
+			},
+			wantErr: errors2.ErrRefreshTokenIsInvalid,
+		},
+		{
+			name: "revoked user",
+			ttl:  time.Hour,
+			prepare: func(rt *RefreshTokens, token access.RefreshToken) {
+				require.NoError(t, rt.RevokeUser(context.Background(), "user"))
+			},
+			wantErr: errors2.ErrRefreshTokenIsInvalid,
+		},
+		{
+			name: "reused token",
+			ttl:  time.Hour,
+			prepare: func(rt *RefreshTokens, token access.RefreshToken) {
+				_, _, err := rt.Rotate(context.Background(), token)
+				require.NoError(t, err)
+			},
+			wantErr: errors2.ErrRefreshTokenIsReused,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewRefreshTokens(tt.ttl)
+			token, err := rt.Issue(context.Background(), testRef("user"))
+			require.NoError(t, err)
+			if tt.prepare != nil {
+				tt.prepare(rt, token)
+			}
+
+			ref, next, err := rt.Rotate(context.Background(), token)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.Equal(t, "user", ref)
+				assert.NotEqual(t, token, next)
+			}
+		})
+	}
+}
+
+func TestRefreshTokens_Rotate_ReuseRevokesFamily(t *testing.T) {
+	rt := NewRefreshTokens(time.Hour)
+	first, err := rt.Issue(context.Background(), testRef("user"))
+	require.NoError(t, err)
+	other, err := rt.Issue(context.Background(), testRef("user"))
+	require.NoError(t, err)
+	_, second, err := rt.Rotate(context.Background(), first)
+	require.NoError(t, err)
+
+	_, _, err = rt.Rotate(context.Background(), first)
+	require.ErrorIs(t, err, errors2.ErrRefreshTokenIsReused)
+	_, _, err = rt.Rotate(context.Background(), second)
+	assert.ErrorIs(t, err, errors2.ErrRefreshTokenIsInvalid)
+	_, _, err = rt.Rotate(context.Background(), other)
+	assert.NoError(t, err, "other family must stay alive")
+}
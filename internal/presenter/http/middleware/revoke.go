@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+)
+
+// AccessRevoker отзывает доступы пользователя вне его собственного запроса,
+// например, когда учетную запись отключает администратор
+type AccessRevoker struct {
+	sessions access.SessionStore
+	refresh  access.RefreshTokenStore
+}
+
+func NewAccessRevoker(sessions access.SessionStore, refresh access.RefreshTokenStore) *AccessRevoker {
+	if sessions == nil {
+		panic("missing access.SessionStore, parameter must not be nil")
+	}
+	if refresh == nil {
+		panic("missing access.RefreshTokenStore, parameter must not be nil")
+	}
+	return &AccessRevoker{sessions: sessions, refresh: refresh}
+}
+
+// RevokeUser закрывает все сессии и отзывает все refresh-токены пользователя.
+// Уже выданные access-токены отклоняет BearerToken, который проверяет статус пользователя.
+func (ar *AccessRevoker) RevokeUser(ctx context.Context, userRef access.Referencer) error {
+	err := ar.sessions.DeleteUserSessions(ctx, userRef.Reference())
+	if err != nil {
+		return err
+	}
+	return ar.refresh.RevokeUser(ctx, userRef.Reference())
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+)
+
+// ContextUserRoleKey роль пользователя лежит в контексте рядом с ContextUserIDKey
+var ContextUserRoleKey = LocalContext("GopherMartUserRole")
+
+// RoleReader читает роль пользователя.
+// Если пользователь не найден, отключен или удален - возвращает errors.ErrUserNotFound
+type RoleReader interface {
+	UserRole(ctx context.Context, usr user.User) (user.Role, error)
+}
+
+// Roles кладет в контекст роль пользователя, которого определил предыдущий middleware по сессии или токену.
+// Роль читается на каждый запрос, поэтому смена роли действует сразу.
+func Roles(reader RoleReader) func(next http.Handler) http.Handler {
+	if reader == nil {
+		panic("missing RoleReader, parameter must not be nil")
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ref, ok := r.Context().Value(ContextUserIDKey).(string)
+			if !ok || ref == "" {
+				utils.ServerError(w, errors2.ErrSessionUserCanNotBeDefined, http.StatusUnauthorized)
+				return
+			}
+			role, err := reader.UserRole(r.Context(), user.User{ID: ref})
+			if err != nil {
+				if errors.Is(err, errors2.ErrUserNotFound) {
+					utils.ServerError(w, errors2.ErrSessionUserCanNotBeDefined, http.StatusUnauthorized)
+					return
+				}
+				utils.InternalServerError(w, err)
+				return
+			}
+			ctx := context.WithValue(r.Context(), ContextUserRoleKey, role)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole пропускает только пользователей с одной из ролей, остальным - 403.
+// Должен стоять после Roles.
+func RequireRole(roles ...user.Role) func(next http.Handler) http.Handler {
+	if len(roles) == 0 {
+		panic("missing role, at least one must be set")
+	}
+	allowed := make(map[user.Role]struct{}, len(roles))
+	for _, role := range roles {
+		allowed[role] = struct{}{}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, _ := r.Context().Value(ContextUserRoleKey).(user.Role)
+			if _, ok := allowed[role]; !ok {
+				utils.ServerError(w, errors2.ErrAccessDenied, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
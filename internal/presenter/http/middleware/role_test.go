@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRoles роли пользователей по ссылке
+type testRoles map[string]struct {
+	role user.Role
+	err  error
+}
+
+func (tr testRoles) UserRole(_ context.Context, usr user.User) (user.Role, error) {
+	return tr[usr.ID].role, tr[usr.ID].err
+}
+
+func TestRoles(t *testing.T) {
+	reader := testRoles{
+		"admin":   {role: user.RoleAdmin},
+		"user":    {role: user.RoleUser},
+		"deleted": {err: errors2.ErrUserNotFound},
+		"broken":  {err: errors.New("dummy error")},
+	}
+	tests := []struct {
+		name    string
+		ref     string
+		require []user.Role
+		want    int
+	}{
+		{name: "no user in context", require: []user.Role{user.RoleAdmin}, want: http.StatusUnauthorized},
+		{name: "user is deleted", ref: "deleted", require: []user.Role{user.RoleAdmin}, want: http.StatusUnauthorized},
+		{name: "reader error", ref: "broken", require: []user.Role{user.RoleAdmin}, want: http.StatusInternalServerError},
+		{name: "role is not allowed", ref: "user", require: []user.Role{user.RoleAdmin, user.RoleSupport}, want: http.StatusForbidden},
+		{name: "role is allowed", ref: "admin", require: []user.Role{user.RoleAdmin, user.RoleSupport}, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.ref != "" {
+				request = request.WithContext(context.WithValue(request.Context(), ContextUserIDKey, tt.ref))
+			}
+			w := httptest.NewRecorder()
+
+			var role user.Role
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				role, _ = r.Context().Value(ContextUserRoleKey).(user.Role)
+			})
+			Roles(reader)(RequireRole(tt.require...)(next)).ServeHTTP(w, request)
+			assert.Equal(t, tt.want, w.Code)
+			if tt.want == http.StatusOK {
+				assert.Equal(t, user.RoleAdmin, role)
+			}
+		})
+	}
+}
@@ -1,28 +1,34 @@
 package middleware
 
 import (
+	"context"
+	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
 )
 
-const sessionTTL = 120 * time.Second
-
-type Referencer interface {
-	Reference() string
-}
+const (
+	DefaultSessionIdleTTL  = 30 * time.Minute
+	DefaultSessionLifetime = 24 * time.Hour
+)
 
 type session struct {
-	userRef Referencer
-	expiry  time.Time
+	userRef  string
+	expiry   time.Time
+	deadline time.Time
 }
 
-func newSession(userRef Referencer, ttl time.Duration) session {
+func newSession(userRef access.Referencer, idleTTL, lifetime time.Duration) session {
 	if userRef == nil {
 		panic("missing app.Referencer, parameter must not be nil")
 	}
-	s := session{userRef: userRef}
-	s.refresh(ttl)
+	s := session{
+		userRef:  userRef.Reference(),
+		deadline: time.Now().Add(lifetime),
+	}
+	s.refresh(idleTTL)
 	return s
 }
 
@@ -30,60 +36,90 @@ func (s *session) isExpired() bool {
 	return s.expiry.Before(time.Now())
 }
 
-func (s *session) refresh(ttl time.Duration) {
-	s.expiry = time.Now().Add(ttl)
-}
-
-func (s *session) getReference() string {
-	return s.userRef.Reference()
+// refresh продлевает сессию на время простоя, но не дальше ее абсолютного срока жизни
+func (s *session) refresh(idleTTL time.Duration) {
+	s.expiry = time.Now().Add(idleTTL)
+	if s.expiry.After(s.deadline) {
+		s.expiry = s.deadline
+	}
 }
 
-type SessionToken string
-
-func NewSessionToken() SessionToken {
-	return SessionToken(uuid.New().String())
-}
+var _ access.SessionStore = (*Sessions)(nil)
 
+// Sessions хранит сессии в памяти. Все сессии теряются при перезапуске сервиса.
 type Sessions struct {
-	ttl   time.Duration
-	store map[SessionToken]session
+	idleTTL  time.Duration
+	lifetime time.Duration
+	mu       sync.Mutex
+	store    map[access.SessionToken]session
 }
 
-func NewSessions(ttl time.Duration) *Sessions {
+// NewSessions idleTTL - время, через которое неиспользуемая сессия истекает,
+// lifetime - время, через которое сессия истекает независимо от активности пользователя
+func NewSessions(idleTTL, lifetime time.Duration) *Sessions {
 	return &Sessions{
-		ttl:   ttl,
-		store: make(map[SessionToken]session, 8),
+		idleTTL:  idleTTL,
+		lifetime: lifetime,
+		store:    make(map[access.SessionToken]session, 8),
 	}
 }
 
 func NewDefaultSessions() *Sessions {
-	return NewSessions(sessionTTL)
+	return NewSessions(DefaultSessionIdleTTL, DefaultSessionLifetime)
 }
 
-func (s *Sessions) AddNewSession(userRef Referencer) SessionToken {
+func (s *Sessions) AddNewSession(_ context.Context, userRef access.Referencer) (access.SessionToken, error) {
 	if userRef == nil {
 		panic("missing app.Referencer, parameter must not be nil")
 	}
-	token := NewSessionToken()
-	s.store[token] = newSession(userRef, s.ttl)
-	return token
+	token := access.NewSessionToken()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store[token] = newSession(userRef, s.idleTTL, s.lifetime)
+	return token, nil
 }
 
-func (s *Sessions) IsExpired(token SessionToken) bool {
-	if session, ok := s.store[token]; ok {
-		if expired := session.isExpired(); expired {
+func (s *Sessions) GetReference(_ context.Context, token access.SessionToken) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.store[token]
+	if !ok {
+		return "", errors2.ErrSessionIsExpired
+	}
+	if sess.isExpired() {
+		delete(s.store, token)
+		return "", errors2.ErrSessionIsExpired
+	}
+	sess.refresh(s.idleTTL)
+	s.store[token] = sess
+	return sess.userRef, nil
+}
+
+func (s *Sessions) DeleteSession(_ context.Context, token access.SessionToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.store, token)
+	return nil
+}
+
+func (s *Sessions) DeleteUserSessions(_ context.Context, userRef string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for token, sess := range s.store {
+		if sess.userRef == userRef {
 			delete(s.store, token)
-			return true
 		}
-		session.refresh(s.ttl)
-		return false
 	}
-	return true
+	return nil
 }
 
-func (s *Sessions) GetReference(token SessionToken) string {
-	if session, ok := s.store[token]; ok {
-		return session.userRef.Reference()
+func (s *Sessions) DeleteExpired(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for token, sess := range s.store {
+		if sess.isExpired() {
+			delete(s.store, token)
+		}
 	}
-	return ""
+	return nil
 }
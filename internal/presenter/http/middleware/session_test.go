@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRef string
+
+func (r testRef) Reference() string {
+	return string(r)
+}
+
+func TestSessions_GetReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		prepare func(s *Sessions, token access.SessionToken)
+		wantRef string
+		wantErr error
+	}{
+		{
+			name:    "active session",
+			ttl:     time.Minute,
+			wantRef: "user",
+		},
+		{
+			name:    "expired session",
+			ttl:     -time.Second,
+			wantErr: errors2.ErrSessionIsExpired,
+		},
+		{
+			name: "deleted session",
+			ttl:  time.Minute,
+			prepare: func(s *Sessions, token access.SessionToken) {
+				require.NoError(t, s.DeleteSession(context.Background(), token))
+			},
+			wantErr: errors2.ErrSessionIsExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSessions(tt.ttl, time.Hour)
+			token, err := s.AddNewSession(context.Background(), testRef("user"))
+			require.NoError(t, err)
+			if tt.prepare != nil {
+				tt.prepare(s, token)
+			}
+
+			ref, err := s.GetReference(context.Background(), token)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantRef, ref)
+		})
+	}
+}
+
+func TestSessions_GetReference_Refresh(t *testing.T) {
+	s := NewSessions(time.Minute, time.Hour)
+	token, err := s.AddNewSession(context.Background(), testRef("user"))
+	require.NoError(t, err)
+	before := s.store[token].expiry
+
+	time.Sleep(time.Millisecond)
+	_, err = s.GetReference(context.Background(), token)
+	require.NoError(t, err)
+	assert.True(t, s.store[token].expiry.After(before))
+}
+
+func TestSessions_GetReference_Lifetime(t *testing.T) {
+	s := NewSessions(time.Hour, time.Minute)
+	token, err := s.AddNewSession(context.Background(), testRef("user"))
+	require.NoError(t, err)
+
+	_, err = s.GetReference(context.Background(), token)
+	require.NoError(t, err)
+	sess := s.store[token]
+	assert.Equal(t, sess.deadline, sess.expiry)
+}
+
+func TestSessions_DeleteExpired(t *testing.T) {
+	s := NewSessions(time.Minute, time.Hour)
+	active, err := s.AddNewSession(context.Background(), testRef("active"))
+	require.NoError(t, err)
+	s.idleTTL = -time.Second
+	_, err = s.AddNewSession(context.Background(), testRef("expired"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeleteExpired(context.Background()))
+	assert.Len(t, s.store, 1)
+	assert.Contains(t, s.store, active)
+}
+
+func TestSessions_DeleteUserSessions(t *testing.T) {
+	s := NewDefaultSessions()
+	for _, ref := range []testRef{"user", "user", "other"} {
+		_, err := s.AddNewSession(context.Background(), ref)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, s.DeleteUserSessions(context.Background(), "user"))
+	assert.Len(t, s.store, 1)
+}
+
+func TestSessions_ConcurrentAccess(t *testing.T) {
+	s := NewDefaultSessions()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := s.AddNewSession(context.Background(), testRef("user"))
+			assert.NoError(t, err)
+			_, err = s.GetReference(context.Background(), token)
+			assert.NoError(t, err)
+			assert.NoError(t, s.DeleteExpired(context.Background()))
+			assert.NoError(t, s.DeleteSession(context.Background(), token))
+		}()
+	}
+	wg.Wait()
+	assert.Empty(t, s.store)
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+)
+
+const (
+	RetryAfterHeader = "Retry-After"
+
+	loginKeyPrefix = "login:"
+	ipKeyPrefix    = "ip:"
+)
+
+// ThrottlePolicy после Limit неудач подряд ключ блокируется на BaseDelay,
+// каждая следующая неудача удваивает блокировку, но не больше MaxDelay
+type ThrottlePolicy struct {
+	Limit     int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Delay 0 - блокировать не нужно
+func (p ThrottlePolicy) Delay(failures int) time.Duration {
+	if p.Limit <= 0 || failures < p.Limit {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.Limit; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// Throttle ограничивает подбор паролей: неудачи считаются отдельно по логину и по IP клиента.
+// Попытка учитывается заранее, поэтому параллельная серия попыток упирается в блокировку так же, как последовательная
+type Throttle struct {
+	store access.AttemptStore
+	login ThrottlePolicy
+	ip    ThrottlePolicy
+}
+
+func NewThrottle(store access.AttemptStore, login, ip ThrottlePolicy) *Throttle {
+	if store == nil {
+		panic("missing access.AttemptStore, parameter must not be nil")
+	}
+	return &Throttle{store: store, login: login, ip: ip}
+}
+
+// Acquire учитывает попытку входа до проверки пароля. Возвращает, сколько осталось ждать до следующей попытки,
+// 0 - попытка разрешена. Разрешенная попытка считается неудачной, пока не вызван Succeed или Release
+func (t *Throttle) Acquire(ctx context.Context, login string, r *http.Request) (time.Duration, error) {
+	loginKey := loginKeyPrefix + login
+	until, err := t.store.Acquire(ctx, loginKey, t.login.Delay)
+	if err != nil || !until.IsZero() {
+		return wait(until), err
+	}
+	until, err = t.store.Acquire(ctx, ipKeyPrefix+ClientIP(r), t.ip.Delay)
+	if err != nil || !until.IsZero() {
+		// попытка так и не состоялась
+		releaseErr := t.store.Release(ctx, loginKey, t.login.Delay)
+		if err == nil {
+			err = releaseErr
+		}
+		return wait(until), err
+	}
+	return 0, nil
+}
+
+// Succeed сбрасывает счетчик логина, а для IP только отменяет учет попытки.
+// Счетчик IP не сбрасывается, иначе вход в свою учетную запись позволял бы продолжать подбор чужих паролей
+func (t *Throttle) Succeed(ctx context.Context, login string, r *http.Request) error {
+	err := t.store.Reset(ctx, loginKeyPrefix+login)
+	if err != nil {
+		return err
+	}
+	return t.store.Release(ctx, ipKeyPrefix+ClientIP(r), t.ip.Delay)
+}
+
+// Release отменяет учет попытки, которая не была ни удачной, ни неудачной, например, закончилась ошибкой сервера
+func (t *Throttle) Release(ctx context.Context, login string, r *http.Request) error {
+	err := t.store.Release(ctx, loginKeyPrefix+login, t.login.Delay)
+	if err != nil {
+		return err
+	}
+	return t.store.Release(ctx, ipKeyPrefix+ClientIP(r), t.ip.Delay)
+}
+
+func wait(until time.Time) time.Duration {
+	if until.IsZero() {
+		return 0
+	}
+	if d := time.Until(until); d > 0 {
+		return d
+	}
+	// блокировка истекла, пока шел запрос, но попытка уже отклонена
+	return time.Second
+}
+
+// SetRetryAfter округляет ожидание вверх до секунды
+func SetRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	secs := int64((wait + time.Second - 1) / time.Second)
+	w.Header().Set(RetryAfterHeader, strconv.FormatInt(secs, 10))
+}
+
+// ClientIP адрес клиента. RealIP подменяет RemoteAddr адресом из X-Forwarded-For или X-Real-IP,
+// только если запрос пришел от доверенного прокси.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type attempt struct {
+	failures int
+	lastFail time.Time
+	locked   time.Time
+}
+
+var _ access.AttemptStore = (*Attempts)(nil)
+
+// Attempts хранит счетчики в памяти. Подходит только для одного экземпляра сервиса.
+type Attempts struct {
+	window time.Duration
+	mu     sync.Mutex
+	store  map[string]attempt
+}
+
+func NewAttempts(window time.Duration) *Attempts {
+	return &Attempts{
+		window: window,
+		store:  make(map[string]attempt, 8),
+	}
+}
+
+func (a *Attempts) Acquire(_ context.Context, key string, lock func(attempts int) time.Duration) (time.Time, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	at := a.store[key]
+	if at.locked.After(now) {
+		return at.locked, nil
+	}
+	if now.Sub(at.lastFail) > a.window {
+		at.failures = 0
+	}
+	at.failures++
+	at.lastFail = now
+	at.locked = time.Time{}
+	if delay := lock(at.failures); delay > 0 {
+		at.locked = now.Add(delay)
+	}
+	a.store[key] = at
+	return time.Time{}, nil
+}
+
+func (a *Attempts) Release(_ context.Context, key string, lock func(attempts int) time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	at, ok := a.store[key]
+	if !ok {
+		return nil
+	}
+	if at.failures > 0 {
+		at.failures--
+	}
+	if lock(at.failures) <= 0 {
+		at.locked = time.Time{}
+	}
+	a.store[key] = at
+	return nil
+}
+
+func (a *Attempts) Reset(_ context.Context, key string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.store, key)
+	return nil
+}
+
+func (a *Attempts) DeleteExpired(_ context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	for key, at := range a.store {
+		if now.Sub(at.lastFail) > a.window && now.After(at.locked) {
+			delete(a.store, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottlePolicy_Delay(t *testing.T) {
+	policy := ThrottlePolicy{Limit: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: 0},
+		{failures: 2, want: 0},
+		{failures: 3, want: time.Second},
+		{failures: 4, want: 2 * time.Second},
+		{failures: 5, want: 4 * time.Second},
+		{failures: 7, want: 10 * time.Second},
+		{failures: 1000, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, policy.Delay(tt.failures), "failures %d", tt.failures)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	policy := ThrottlePolicy{Limit: 2, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	ipPolicy := ThrottlePolicy{Limit: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	ctx := context.Background()
+	request := func(ip string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.RemoteAddr = ip + ":1234"
+		return r
+	}
+	acquire := func(t *testing.T, th *Throttle, login, ip string) time.Duration {
+		wait, err := th.Acquire(ctx, login, request(ip))
+		require.NoError(t, err)
+		return wait
+	}
+
+	t.Run("login is locked", func(t *testing.T) {
+		th := NewThrottle(NewAttempts(time.Hour), policy, ipPolicy)
+		assert.Zero(t, acquire(t, th, "user", "10.0.0.1"))
+		assert.Zero(t, acquire(t, th, "user", "10.0.0.2"))
+		assert.InDelta(t, time.Minute, acquire(t, th, "user", "10.0.0.3"), float64(time.Second))
+	})
+	t.Run("ip is locked for every login", func(t *testing.T) {
+		th := NewThrottle(NewAttempts(time.Hour), policy, ipPolicy)
+		for _, login := range []string{"a", "b", "c"} {
+			assert.Zero(t, acquire(t, th, login, "10.0.0.1"))
+		}
+		assert.Greater(t, acquire(t, th, "d", "10.0.0.1"), time.Duration(0))
+		assert.Zero(t, acquire(t, th, "d", "10.0.0.2"))
+	})
+	t.Run("locked ip does not count login attempt", func(t *testing.T) {
+		store := NewAttempts(time.Hour)
+		th := NewThrottle(store, policy, ipPolicy)
+		for _, login := range []string{"a", "b", "c"} {
+			acquire(t, th, login, "10.0.0.1")
+		}
+		acquire(t, th, "d", "10.0.0.1")
+		assert.Zero(t, store.store[loginKeyPrefix+"d"].failures)
+	})
+	t.Run("success resets login only", func(t *testing.T) {
+		store := NewAttempts(time.Hour)
+		th := NewThrottle(store, policy, ipPolicy)
+		acquire(t, th, "user", "10.0.0.1")
+		acquire(t, th, "user", "10.0.0.1")
+		require.NoError(t, th.Succeed(ctx, "user", request("10.0.0.1")))
+		assert.NotContains(t, store.store, loginKeyPrefix+"user")
+		assert.Equal(t, 1, store.store[ipKeyPrefix+"10.0.0.1"].failures)
+	})
+	t.Run("release undoes attempt", func(t *testing.T) {
+		store := NewAttempts(time.Hour)
+		th := NewThrottle(store, policy, ipPolicy)
+		acquire(t, th, "user", "10.0.0.1")
+		acquire(t, th, "user", "10.0.0.1")
+		require.NoError(t, th.Release(ctx, "user", request("10.0.0.1")))
+		assert.Equal(t, 1, store.store[loginKeyPrefix+"user"].failures)
+		assert.Zero(t, acquire(t, th, "user", "10.0.0.1"))
+	})
+	t.Run("parallel attempts do not pass the limit", func(t *testing.T) {
+		th := NewThrottle(NewAttempts(time.Hour), policy, ThrottlePolicy{})
+		var (
+			wg      sync.WaitGroup
+			allowed int32
+		)
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				wait, err := th.Acquire(ctx, "user", request("10.0.0.1"))
+				if err == nil && wait == 0 {
+					atomic.AddInt32(&allowed, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(policy.Limit), allowed)
+	})
+}
+
+func TestAttempts_Acquire_Window(t *testing.T) {
+	a := NewAttempts(time.Minute)
+	never := func(int) time.Duration { return 0 }
+	_, err := a.Acquire(context.Background(), "key", never)
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.store["key"].failures)
+
+	at := a.store["key"]
+	at.lastFail = time.Now().Add(-2 * time.Minute)
+	a.store["key"] = at
+	_, err = a.Acquire(context.Background(), "key", never)
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.store["key"].failures)
+}
+
+func TestAttempts_DeleteExpired(t *testing.T) {
+	a := NewAttempts(time.Minute)
+	old := time.Now().Add(-2 * time.Minute)
+	a.store["expired"] = attempt{failures: 1, lastFail: old}
+	a.store["locked"] = attempt{failures: 9, lastFail: old, locked: time.Now().Add(time.Minute)}
+	a.store["recent"] = attempt{failures: 1, lastFail: time.Now()}
+
+	require.NoError(t, a.DeleteExpired(context.Background()))
+	assert.NotContains(t, a.store, "expired")
+	assert.Contains(t, a.store, "locked")
+	assert.Contains(t, a.store, "recent")
+}
+
+func TestSetRetryAfter(t *testing.T) {
+	w := httptest.NewRecorder()
+	SetRetryAfter(w, 1500*time.Millisecond)
+	assert.Equal(t, "2", w.Header().Get(RetryAfterHeader))
+}
@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/utils"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AuthorizationHeader = "Authorization"
+	bearerScheme        = "Bearer"
+)
+
+var (
+	ErrBearerTokenNotSet       = errors.New("bearer token is not set")
+	ErrBearerTokenInvalid      = errors.New("bearer token is invalid or expired")
+	ErrRefreshTokenNotSet      = errors.New("refresh token is not set")
+	ErrTokenSigningKeyNotSet   = errors.New("current token signing key is not set")
+	ErrTokenSigningKeyInvalid  = errors.New("token signing key is invalid")
+	ErrTokenSigningKeyUnknown  = errors.New("token is signed with unknown key")
+	ErrTokenSubjectNotSet      = errors.New("token subject is not set")
+	ErrTokenIssuerIsNotTrusted = errors.New("token issuer is not trusted")
+)
+
+// Tokens выпускает и проверяет JWT, в которых subject - ссылка на пользователя.
+// Как и в Keyring, подписывается всегда текущим ключом, а проверяется любым из набора.
+type Tokens struct {
+	method     jwt.SigningMethod
+	currentID  string
+	signKey    interface{}
+	verifyKeys map[string]interface{}
+	ttl        time.Duration
+	issuer     string
+}
+
+// NewHS256Tokens токены с симметричной подписью ключами из keys
+func NewHS256Tokens(keys *Keyring, ttl time.Duration, issuer string) *Tokens {
+	if keys == nil {
+		panic("missing *Keyring, parameter must not be nil")
+	}
+	t := &Tokens{
+		method:     jwt.SigningMethodHS256,
+		verifyKeys: make(map[string]interface{}, len(keys.keys)),
+		ttl:        ttl,
+		issuer:     issuer,
+	}
+	var key []byte
+	t.currentID, key = keys.current()
+	t.signKey = key
+	for id, key := range keys.keys {
+		t.verifyKeys[id] = key
+	}
+	return t
+}
+
+// NewEdDSATokens токены с асимметричной подписью, проверка идет по публичным частям ключей
+func NewEdDSATokens(currentID string, keys map[string]ed25519.PrivateKey, ttl time.Duration, issuer string) (*Tokens, error) {
+	if _, ok := keys[currentID]; !ok {
+		return nil, ErrTokenSigningKeyNotSet
+	}
+	t := &Tokens{
+		method:     jwt.SigningMethodEdDSA,
+		currentID:  currentID,
+		signKey:    keys[currentID],
+		verifyKeys: make(map[string]interface{}, len(keys)),
+		ttl:        ttl,
+		issuer:     issuer,
+	}
+	for id, key := range keys {
+		if id == "" || len(key) != ed25519.PrivateKeySize {
+			return nil, ErrTokenSigningKeyInvalid
+		}
+		t.verifyKeys[id] = key.Public()
+	}
+	return t, nil
+}
+
+// Issue выпускает токен для пользователя
+func (t *Tokens) Issue(userRef access.Referencer) (token string, expiresAt time.Time, err error) {
+	if userRef == nil {
+		panic("missing access.Referencer, parameter must not be nil")
+	}
+	now := time.Now()
+	expiresAt = now.Add(t.ttl)
+	claims := jwt.RegisteredClaims{
+		Issuer:    t.issuer,
+		Subject:   userRef.Reference(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	jwtToken := jwt.NewWithClaims(t.method, claims)
+	jwtToken.Header["kid"] = t.currentID
+	token, err = jwtToken.SignedString(t.signKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// Parse проверяет токен и возвращает ссылку на пользователя
+func (t *Tokens) Parse(token string) (ref string, err error) {
+	claims := jwt.RegisteredClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{t.method.Alg()}))
+	_, err = parser.ParseWithClaims(token, &claims, func(jwtToken *jwt.Token) (interface{}, error) {
+		kid, _ := jwtToken.Header["kid"].(string)
+		key, ok := t.verifyKeys[kid]
+		if !ok {
+			return nil, ErrTokenSigningKeyUnknown
+		}
+		return key, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if !claims.VerifyIssuer(t.issuer, true) {
+		return "", ErrTokenIssuerIsNotTrusted
+	}
+	if claims.Subject == "" {
+		return "", ErrTokenSubjectNotSet
+	}
+	return claims.Subject, nil
+}
+
+// ActiveChecker проверяет, что пользователь все еще не отключен и не удален
+type ActiveChecker interface {
+	// CheckActive возвращает errors.ErrUserIsDisabled, если пользователь отключен, errors.ErrUserNotFound - если удален
+	CheckActive(ctx context.Context, usr user.User) error
+}
+
+// BearerToken кладет в контекст пользователя из токена в заголовке Authorization.
+// Токен нельзя отозвать до истечения срока, поэтому статус пользователя проверяется на каждый запрос.
+func BearerToken(tokens *Tokens, users ActiveChecker) func(next http.Handler) http.Handler {
+	if tokens == nil {
+		panic("missing *Tokens, parameter must not be nil")
+	}
+	if users == nil {
+		panic("missing ActiveChecker, parameter must not be nil")
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := getBearerToken(r)
+			if !ok {
+				utils.ServerError(w, ErrBearerTokenNotSet, http.StatusUnauthorized)
+				return
+			}
+			ref, err := tokens.Parse(token)
+			if err != nil {
+				utils.ServerError(w, ErrBearerTokenInvalid, http.StatusUnauthorized)
+				return
+			}
+			err = users.CheckActive(r.Context(), user.User{ID: ref})
+			switch {
+			case errors.Is(err, errors2.ErrUserIsDisabled):
+				utils.ServerError(w, err, http.StatusForbidden)
+				return
+			case errors.Is(err, errors2.ErrUserNotFound):
+				utils.ServerError(w, ErrBearerTokenInvalid, http.StatusUnauthorized)
+				return
+			case err != nil:
+				utils.InternalServerError(w, err)
+				return
+			}
+			ctx := context.WithValue(r.Context(), ContextUserIDKey, ref)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// BearerTokenOrCookie проверяет токен, если клиент прислал заголовок Authorization, иначе - сессионную куку
+func BearerTokenOrCookie(bearer, cookie func(next http.Handler) http.Handler) func(next http.Handler) http.Handler {
+	if bearer == nil || cookie == nil {
+		panic("missing middleware, parameters must not be nil")
+	}
+	return func(next http.Handler) http.Handler {
+		withBearer, withCookie := bearer(next), cookie(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(AuthorizationHeader) != "" {
+				withBearer.ServeHTTP(w, r)
+				return
+			}
+			withCookie.ServeHTTP(w, r)
+		})
+	}
+}
+
+func getBearerToken(r *http.Request) (token string, ok bool) {
+	ss := strings.SplitN(r.Header.Get(AuthorizationHeader), " ", 2)
+	if len(ss) != 2 || !strings.EqualFold(ss[0], bearerScheme) || strings.TrimSpace(ss[1]) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(ss[1]), true
+}
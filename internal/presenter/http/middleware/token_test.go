@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	errors2 "github.com/UndeadDemidov/ya-pr-diploma/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokens_Parse(t *testing.T) {
+	_, oldKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	before, err := NewEdDSATokens("1", map[string]ed25519.PrivateKey{"1": oldKey}, time.Hour, "test")
+	require.NoError(t, err)
+	rotated, err := NewEdDSATokens("2", map[string]ed25519.PrivateKey{"1": oldKey, "2": newKey}, time.Hour, "test")
+	require.NoError(t, err)
+	retired, err := NewEdDSATokens("2", map[string]ed25519.PrivateKey{"2": newKey}, time.Hour, "test")
+	require.NoError(t, err)
+	otherIssuer, err := NewEdDSATokens("1", map[string]ed25519.PrivateKey{"1": oldKey}, time.Hour, "other")
+	require.NoError(t, err)
+	expired, err := NewEdDSATokens("1", map[string]ed25519.PrivateKey{"1": oldKey}, -time.Minute, "test")
+	require.NoError(t, err)
+	hmacKeys, err := NewKeyring("1", map[string][]byte{"1": oldKey.Seed()})
+	require.NoError(t, err)
+	hs256 := NewHS256Tokens(hmacKeys, time.Hour, "test")
+
+	tests := []struct {
+		name    string
+		issuer  *Tokens
+		parser  *Tokens
+		wantErr bool
+	}{
+		{name: "same key", issuer: before, parser: before},
+		{name: "old key after rotation", issuer: before, parser: rotated},
+		{name: "retired key", issuer: before, parser: retired, wantErr: true},
+		{name: "untrusted issuer", issuer: otherIssuer, parser: before, wantErr: true},
+		{name: "expired", issuer: expired, parser: before, wantErr: true},
+		{name: "algorithm substitution", issuer: hs256, parser: before, wantErr: true},
+		{name: "hs256", issuer: hs256, parser: hs256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _, err := tt.issuer.Issue(testRef("user"))
+			require.NoError(t, err)
+			ref, err := tt.parser.Parse(token)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user", ref)
+		})
+	}
+}
+
+// testUsers статусы пользователей: ошибка CheckActive по ссылке, по умолчанию пользователь активен
+type testUsers map[string]error
+
+func (u testUsers) CheckActive(_ context.Context, usr user.User) error {
+	return u[usr.ID]
+}
+
+func TestBearerToken_CheckActive(t *testing.T) {
+	tokens := NewHS256Tokens(NewRandomKeyring(), time.Hour, "test")
+	users := testUsers{
+		"disabled": errors2.ErrUserIsDisabled,
+		"deleted":  errors2.ErrUserNotFound,
+		"broken":   errors.New("connection refused"),
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+		want int
+	}{
+		{name: "active", ref: "user", want: http.StatusOK},
+		{name: "disabled", ref: "disabled", want: http.StatusForbidden},
+		{name: "deleted", ref: "deleted", want: http.StatusUnauthorized},
+		{name: "storage error", ref: "broken", want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _, err := tokens.Issue(testRef(tt.ref))
+			require.NoError(t, err)
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set(AuthorizationHeader, "Bearer "+token)
+			w := httptest.NewRecorder()
+
+			BearerToken(tokens, users)(next).ServeHTTP(w, request)
+			result := w.Result()
+			defer result.Body.Close()
+			assert.Equal(t, tt.want, result.StatusCode)
+			assert.Equal(t, tt.want == http.StatusOK, called)
+		})
+	}
+}
+
+func TestBearerTokenOrCookie(t *testing.T) {
+	tokens := NewHS256Tokens(NewRandomKeyring(), time.Hour, "test")
+	token, _, err := tokens.Issue(testRef("user"))
+	require.NoError(t, err)
+	opts := DefaultCookieOptions()
+	sessions := NewDefaultSessions()
+	granter := NewCookieGranter(sessions, opts)
+	w := httptest.NewRecorder()
+	_, err = granter.Grant(w, httptest.NewRequest(http.MethodPost, "/", nil), testRef("user"))
+	require.NoError(t, err)
+	cookie := w.Result().Cookies()[0]
+
+	tests := []struct {
+		name   string
+		header string
+		cookie *http.Cookie
+		want   int
+	}{
+		{name: "nothing", want: http.StatusUnauthorized},
+		{name: "bearer token", header: "Bearer " + token, want: http.StatusOK},
+		{name: "lowercase scheme", header: "bearer " + token, want: http.StatusOK},
+		{name: "invalid token", header: "Bearer " + token + "x", want: http.StatusUnauthorized},
+		{name: "basic auth", header: "Basic dXNlcjpwYXNz", want: http.StatusUnauthorized},
+		{name: "cookie", cookie: cookie, want: http.StatusOK},
+		{name: "invalid token wins over cookie", header: "Bearer x", cookie: cookie, want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ref interface{}
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ref = r.Context().Value(ContextUserIDKey)
+			})
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				request.Header.Set(AuthorizationHeader, tt.header)
+			}
+			if tt.cookie != nil {
+				request.AddCookie(tt.cookie)
+			}
+			w := httptest.NewRecorder()
+
+			BearerTokenOrCookie(BearerToken(tokens, testUsers{}), SessionsCookie(sessions, opts.Keys))(next).ServeHTTP(w, request)
+			result := w.Result()
+			defer result.Body.Close()
+			require.Equal(t, tt.want, result.StatusCode)
+			if tt.want == http.StatusOK {
+				assert.Equal(t, "user", ref)
+			}
+		})
+	}
+}
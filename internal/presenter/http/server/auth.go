@@ -0,0 +1,118 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
+	midware "github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// challengeIssuerSuffix токен второго шага входа нельзя предъявить вместо access-токена и наоборот
+const challengeIssuerSuffix = "/2fa"
+
+var ErrEdDSAKeyInvalid = errors.New("EdDSA key must be base64 encoded Ed25519 seed")
+
+// authentication способ выдачи и проверки доступа, выбранный в конфигурации
+type authentication struct {
+	granters []midware.Granter
+	verify   func(next http.Handler) http.Handler
+	// challenges токены между паролем и вторым фактором, подписываются ключами кук в любом режиме
+	challenges *midware.Tokens
+}
+
+func newAuthentication(cfg *conf.App, sessions access.SessionStore, refresh access.RefreshTokenStore,
+	users midware.ActiveChecker) (authn authentication, err error) {
+	keys, err := cookieKeyring(cfg.Session.CookieKeys)
+	if err != nil {
+		return authentication{}, err
+	}
+	authn.challenges = midware.NewHS256Tokens(keys, cfg.TwoFactor.ChallengeTTL, cfg.Auth.TokenIssuer+challengeIssuerSuffix)
+
+	var cookie, bearer func(next http.Handler) http.Handler
+	if cfg.Auth.UseCookie() {
+		opts := cookieOptions(cfg.Session, keys)
+		authn.granters = append(authn.granters, midware.NewCookieGranter(sessions, opts))
+		cookie = midware.SessionsCookie(sessions, opts.Keys)
+	}
+	if cfg.Auth.UseToken() {
+		tokens, err := newTokens(cfg.Auth)
+		if err != nil {
+			return authentication{}, err
+		}
+		authn.granters = append(authn.granters, midware.NewTokenGranter(tokens, refresh))
+		bearer = midware.BearerToken(tokens, users)
+	}
+
+	switch {
+	case cookie != nil && bearer != nil:
+		authn.verify = midware.BearerTokenOrCookie(bearer, cookie)
+	case bearer != nil:
+		authn.verify = bearer
+	default:
+		authn.verify = cookie
+	}
+	return authn, nil
+}
+
+// withRoles после проверки сессии или токена кладет в контекст роль пользователя.
+// Роль читается из БД на каждый запрос, поэтому ставится только на маршруты с RequireRole
+func withRoles(verify func(next http.Handler) http.Handler, roles app.RoleManager) func(next http.Handler) http.Handler {
+	load := midware.Roles(roles)
+	return func(next http.Handler) http.Handler {
+		return verify(load(next))
+	}
+}
+
+// cookieOptions кука живет столько же, сколько сессия может прожить максимально
+func cookieOptions(cfg conf.Session, keys *midware.Keyring) midware.CookieOptions {
+	return midware.CookieOptions{
+		Keys:     keys,
+		MaxAge:   int(cfg.Lifetime.Seconds()),
+		Domain:   cfg.CookieDomain,
+		Secure:   cfg.CookieSecure,
+		HTTPOnly: cfg.CookieHTTPOnly,
+		SameSite: cfg.CookieSameSite,
+	}
+}
+
+func cookieKeyring(cfg []conf.SigningKey) (*midware.Keyring, error) {
+	if len(cfg) == 0 {
+		log.Warn().Msg("session cookie keys are not set, random key is used: sessions and two-factor challenges will not survive restart")
+		return midware.NewRandomKeyring(), nil
+	}
+	return keyring(cfg)
+}
+
+func keyring(cfg []conf.SigningKey) (*midware.Keyring, error) {
+	keys := make(map[string][]byte, len(cfg))
+	for _, k := range cfg {
+		keys[k.ID] = []byte(k.Secret.Reveal())
+	}
+	return midware.NewKeyring(cfg[0].ID, keys)
+}
+
+func newTokens(cfg conf.Auth) (*midware.Tokens, error) {
+	if cfg.TokenAlgorithm == conf.TokenAlgorithmHS256 {
+		keys, err := keyring(cfg.TokenKeys)
+		if err != nil {
+			return nil, err
+		}
+		return midware.NewHS256Tokens(keys, cfg.TokenTTL, cfg.TokenIssuer), nil
+	}
+
+	keys := make(map[string]ed25519.PrivateKey, len(cfg.TokenKeys))
+	for _, k := range cfg.TokenKeys {
+		seed, err := base64.StdEncoding.DecodeString(k.Secret.Reveal())
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, ErrEdDSAKeyInvalid
+		}
+		keys[k.ID] = ed25519.NewKeyFromSeed(seed)
+	}
+	return midware.NewEdDSATokens(cfg.TokenKeys[0].ID, keys, cfg.TokenTTL, cfg.TokenIssuer)
+}
@@ -5,13 +5,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/app"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/app/access"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/conf"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/auth"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/primit"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/service"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/domains/user"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/infra/accrual"
+	"github.com/UndeadDemidov/ya-pr-diploma/internal/infra/notify"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/infra/persist/postgre"
 	"github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/handler"
 	midware "github.com/UndeadDemidov/ya-pr-diploma/internal/presenter/http/middleware"
@@ -26,7 +32,11 @@ type Server struct {
 	mart     *app.GopherMart
 	srv      *http.Server
 	router   *chi.Mux
-	sessions *midware.Sessions
+	sessions access.SessionStore
+	refresh  access.RefreshTokenStore
+	attempts access.AttemptStore
+	poller   *service.AccrualPoller
+	janitor  *midware.Janitor
 }
 
 func NewServer(cfg *conf.App) (srv *Server, err error) {
@@ -37,7 +47,7 @@ func NewServer(cfg *conf.App) (srv *Server, err error) {
 
 	// ToDo конфигуратор?
 	ctx := context.Background()
-	s.dbPool, err = pgxpool.Connect(ctx, cfg.Database.URI)
+	s.dbPool, err = pgxpool.Connect(ctx, cfg.Database.URI.Reveal())
 	if err != nil {
 		return nil, err
 	}
@@ -46,13 +56,54 @@ func NewServer(cfg *conf.App) (srv *Server, err error) {
 	if err != nil {
 		return nil, err
 	}
-	svcAuth := auth.NewServiceWithDefaultCredMan(repo.Auth, user.NewService(repo.User))
+	err = prepareSchema(ctx, cfg.Database, s.dbPool)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := NewCredentialPolicy(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	credMan := auth.NewManagerWithPolicy(repo.Auth, auth.NewDefaultHasher(), policy)
+	svcUser := user.NewService(repo.User)
+	svcAuth := auth.NewService(svcUser, credMan, repo)
+	svcPassword := auth.NewPasswordService(credMan, repo.PasswordReset, newNotifier(cfg.Notifier), repo)
+	svcTwoFactor := auth.NewTwoFactorService(repo.TwoFactor, cfg.TwoFactor.Issuer, repo)
+	svcOrder := service.NewOrder(repo.Order)
+	svcBalance := service.NewBalance(repo.Balance)
+	svcWithdrawal := service.NewWithdrawal(repo.Withdrawal)
+	s.poller = service.NewAccrualPoller(repo.Order, accrual.NewAdapter(accrual.NewHTTPClient(cfg.AccrualSystemAddress)))
+	svcBackOffice := service.NewBackOffice(repo.User, repo.Order, s.poller)
 	// app configuration
-	s.mart = app.NewGopherMart(svcAuth)
+	s.mart = app.NewGopherMart(svcAuth, svcPassword, svcAuth, svcUser, svcTwoFactor, svcOrder, svcBalance, svcWithdrawal,
+		svcBackOffice)
+	// background workers configuration
+	s.sessions = postgre.NewSession(s.dbPool, cfg.Session.IdleTTL, cfg.Session.Lifetime)
+	s.refresh = postgre.NewRefreshToken(s.dbPool, cfg.Auth.RefreshTokenTTL)
+	s.attempts = newAttemptStore(cfg.Throttle, s.dbPool)
+	s.janitor = midware.NewJanitor(s.sessions, s.refresh, s.attempts)
 	// router configuration
-	s.sessions = midware.NewDefaultSessions()
+	authn, err := newAuthentication(cfg, s.sessions, s.refresh, s.mart)
+	if err != nil {
+		return nil, err
+	}
+	throttle := newThrottle(cfg.Throttle, s.attempts)
 	s.router = s.buildRouter(
-		handler.NewAuth(s.mart, s.sessions),
+		midware.RealIP(cfg.TrustedProxies),
+		authn.verify,
+		// роль нужна только административному API, поэтому и читается только в нем
+		midware.Audit(postgre.NewAuditLog(s.dbPool), repo,
+			midware.AdminKeyOr(cfg.Admin.Key.Reveal(), user.RoleAdmin, withRoles(authn.verify, s.mart.Roles))),
+		handler.NewAuth(s.mart, s.mart.TwoFactor, authn.challenges, throttle, authn.granters...),
+		handler.NewPassword(s.mart.Passwords, authn.granters...),
+		handler.NewAccount(s.mart.Accounts, authn.granters...),
+		handler.NewTwoFactor(s.mart.TwoFactor, throttle),
+		handler.NewOrder(s.mart.Orders),
+		handler.NewBalance(s.mart.Balance),
+		handler.NewWithdrawal(s.mart.Withdrawals, s.mart.TwoFactor, throttle,
+			primit.Float64ToCurrency(cfg.TwoFactor.WithdrawThreshold)),
+		handler.NewAdmin(s.mart.Accounts, s.mart.Roles, midware.NewAccessRevoker(s.sessions, s.refresh)),
+		handler.NewBackOffice(s.mart.BackOffice, s.mart.Orders, s.mart.Balance, s.mart.Withdrawals),
 	)
 
 	s.srv = &http.Server{
@@ -62,10 +113,73 @@ func NewServer(cfg *conf.App) (srv *Server, err error) {
 	return s, nil
 }
 
-func (s *Server) buildRouter(auth *handler.Auth) *chi.Mux {
+// prepareSchema накатывает миграции, если включена автомиграция, иначе только проверяет, что схема актуальна
+func prepareSchema(ctx context.Context, cfg conf.Database, db *pgxpool.Pool) error {
+	migrator, err := postgre.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if cfg.AutoMigrate {
+		return migrator.Up(ctx)
+	}
+	return migrator.CheckUpToDate(ctx)
+}
+
+// NewCredentialPolicy собирает политику логинов и паролей из конфига, список утекших паролей читается из файла
+func NewCredentialPolicy(cfg conf.Credentials) (*auth.CredentialPolicy, error) {
+	var denylist []string
+	if cfg.PasswordDenylist != "" {
+		f, err := os.Open(cfg.PasswordDenylist)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		denylist, err = auth.ReadDenylist(f)
+		if err != nil {
+			return nil, err
+		}
+		log.Info().Msgf("%d breached passwords are loaded", len(denylist))
+	}
+	return auth.NewCredentialPolicy(auth.PolicyRules{
+		LoginMinLen:        cfg.LoginMinLength,
+		LoginMaxLen:        cfg.LoginMaxLength,
+		LoginCharset:       cfg.LoginCharset,
+		PasswordMinLen:     cfg.PasswordMinLength,
+		PasswordMaxLen:     cfg.PasswordMaxLength,
+		PasswordMinClasses: cfg.PasswordMinClasses,
+	}, denylist)
+}
+
+func newNotifier(cfg conf.Notifier) auth.Notifier {
+	if cfg.Kind == conf.NotifierFile {
+		return notify.NewFileNotifier(cfg.File)
+	}
+	return notify.NewLogNotifier()
+}
+
+func newAttemptStore(cfg conf.Throttle, db *pgxpool.Pool) access.AttemptStore {
+	if cfg.Store == conf.ThrottleStorePostgres {
+		return postgre.NewLoginAttempt(db, cfg.Window)
+	}
+	return midware.NewAttempts(cfg.Window)
+}
+
+func newThrottle(cfg conf.Throttle, store access.AttemptStore) *midware.Throttle {
+	return midware.NewThrottle(store,
+		midware.ThrottlePolicy{Limit: cfg.LoginAttempts, BaseDelay: cfg.BaseDelay, MaxDelay: cfg.MaxDelay},
+		midware.ThrottlePolicy{Limit: cfg.IPAttempts, BaseDelay: cfg.BaseDelay, MaxDelay: cfg.MaxDelay},
+	)
+}
+
+// buildRouter realIP - определение адреса клиента за прокси,
+// adminAuth - аутентификация в административном API вместе с журналом действий в нем
+func (s *Server) buildRouter(realIP, verify, adminAuth func(next http.Handler) http.Handler,
+	auth *handler.Auth, pword *handler.Password, account *handler.Account, twoFactor *handler.TwoFactor,
+	order *handler.Order, balance *handler.Balance, wtdrwl *handler.Withdrawal,
+	admin *handler.Admin, backOffice *handler.BackOffice) *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(realIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Compress(5))
@@ -74,9 +188,46 @@ func (s *Server) buildRouter(auth *handler.Auth) *chi.Mux {
 	r.Group(func(r chi.Router) {
 		r.Post("/api/user/register", auth.RegisterUser)
 		r.Post("/api/user/login", auth.LoginUser)
+		r.Post("/api/user/login/2fa", auth.LoginTwoFactor)
+		r.Post("/api/user/token/refresh", auth.RefreshToken)
+		r.Post("/api/user/password/reset", pword.RequestReset)
+		r.Post("/api/user/password/reset/confirm", pword.ConfirmReset)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(verify)
+		r.Post("/api/user/logout", auth.Logout)
+		r.Post("/api/user/logout-all", auth.LogoutAll)
+		r.Put("/api/user/password", pword.Change)
+		r.Delete("/api/user", account.Delete)
+		r.Post("/api/user/2fa/setup", twoFactor.Setup)
+		r.Post("/api/user/2fa/confirm", twoFactor.Confirm)
+		r.Post("/api/user/2fa/disable", twoFactor.Disable)
+		r.Post("/api/user/orders", order.UploadOrder)
+		r.Get("/api/user/orders", order.DownloadOrders)
+		r.Get("/api/user/balance", balance.Get)
+		r.Post("/api/user/balance/withdraw", wtdrwl.CashOut)
+		// в спецификации встречаются оба варианта пути
+		r.Get("/api/user/balance/withdrawals", wtdrwl.History)
+		r.Get("/api/user/withdrawals", wtdrwl.History)
 	})
 	r.Group(func(r chi.Router) {
-		r.Use(midware.SessionsCookie(s.sessions))
+		r.Use(adminAuth)
+		// поддержка только смотрит, менять может только администратор
+		r.Group(func(r chi.Router) {
+			r.Use(midware.RequireRole(user.RoleSupport, user.RoleAdmin))
+			r.Get("/api/admin/users", backOffice.FindUsers)
+			r.Get("/api/admin/users/{id}/orders", backOffice.Orders)
+			r.Get("/api/admin/users/{id}/withdrawals", backOffice.Withdrawals)
+			r.Get("/api/admin/users/{id}/balance", backOffice.Balance)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(midware.RequireRole(user.RoleAdmin))
+			r.Post("/api/admin/orders/{number}/recheck", backOffice.RecheckOrder)
+			r.Post("/api/admin/users/{id}/disable", admin.DisableUser)
+			r.Post("/api/admin/users/{id}/enable", admin.EnableUser)
+			r.Delete("/api/admin/users/{id}", admin.DeleteUser)
+			r.Put("/api/admin/users/{id}/role", admin.ChangeRole)
+		})
 	})
 	return r
 }
@@ -92,6 +243,20 @@ func (s *Server) Run() {
 	}()
 	log.Info().Msg("Server started")
 
+	workers := sync.WaitGroup{}
+	for _, run := range []func(ctx context.Context){s.poller.Run, s.janitor.Run} {
+		workers.Add(1)
+		go func(run func(ctx context.Context)) {
+			defer workers.Done()
+			run(ctx)
+		}(run)
+	}
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+
 	<-ctx.Done()
 
 	log.Info().Msg("Server stopped")
@@ -104,6 +269,11 @@ func (s *Server) Run() {
 	if err := s.srv.Shutdown(ctx); err != nil {
 		log.Error().Msgf("Server Shutdown Failed:%+v", err)
 	}
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		log.Error().Msg("background workers are not stopped in time")
+	}
 	stop()
 	log.Info().Msg("Server exited properly")
 }